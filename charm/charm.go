@@ -1,10 +1,15 @@
 package charm
 
 import (
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
 	"github.com/xhd2015/go-dom-tui/charm/renderer"
 	"github.com/xhd2015/go-dom-tui/dom"
 	"github.com/xhd2015/go-dom-tui/log"
+	"github.com/xhd2015/go-dom-tui/react"
+	"github.com/xhd2015/go-dom-tui/theme"
 )
 
 type CharmApp[T any] struct {
@@ -15,66 +20,64 @@ type CharmApp[T any] struct {
 	height int
 
 	renderer *renderer.InteractiveCharmRenderer
-	dom      *dom.DOM // DOM tree with event handling
+	dom      *dom.DOM     // DOM tree with event handling
+	react    *react.React // hooks runtime for components
+	program  *tea.Program
+
+	keysTimer *time.Timer // renders again when a key sequence times out
+
+	clipboard dom.Clipboard
+	listeners dom.EventListeners
+	ownFocus  bool
 }
 
+// RerenderMsg is sent to the program when component state changes
+// outside of Update, so that the next View reflects it
+type RerenderMsg struct{}
+
 func NewCharmApp[T any](state *T, app func(state *T, window *dom.Window) *dom.Node) *CharmApp[T] {
-	return &CharmApp[T]{
+	r := renderer.NewInteractiveCharmRenderer()
+	c := &CharmApp[T]{
 		State:    state,
 		Root:     app,
-		renderer: renderer.NewInteractiveCharmRenderer(),
+		renderer: r,
+		react:    react.NewReact(r),
 	}
+	c.react.OnUpdate(func() {
+		if c.program != nil {
+			c.program.Send(RerenderMsg{})
+		}
+	})
+	return c
+}
+
+// SetProgram attaches the running program, so that state set through hooks
+// (React.UseState) triggers a re-render
+func (c *CharmApp[T]) SetProgram(p *tea.Program) {
+	c.program = p
+}
+
+// React returns the hooks runtime the app renders with. Components call
+// hooks on it, like UseState, while they render.
+func (c *CharmApp[T]) React() *react.React {
+	return c.react
 }
 
 func (c *CharmApp[T]) Update(msg tea.Msg) {
 	log.Logf("Update: %T", msg)
 	switch msg := msg.(type) {
+	case RerenderMsg:
+		// nothing to do: the following View renders the new state
 	case tea.KeyMsg:
 		log.Logf("Key Msg %v: alt=%v, paste=%v, len(runes)=%v", msg.Type, msg.Alt, msg.Paste, len(msg.Runes))
 		if c.dom != nil {
-			var keyType dom.KeyType
-			switch msg.Type {
-			case tea.KeyUp:
-				keyType = dom.KeyTypeUp
-			case tea.KeyDown:
-				keyType = dom.KeyTypeDown
-			case tea.KeyLeft:
-				keyType = dom.KeyTypeLeft
-			case tea.KeyRight:
-				keyType = dom.KeyTypeRight
-			case tea.KeyEnter:
-				keyType = dom.KeyTypeEnter
-			case tea.KeyBackspace:
-				keyType = dom.KeyTypeBackspace
-			case tea.KeyDelete:
-				keyType = dom.KeyTypeDelete
-			case tea.KeyEscape:
-				keyType = dom.KeyTypeEsc
-			case tea.KeySpace:
-				keyType = dom.KeyTypeSpace
-			case tea.KeyTab:
-				keyType = dom.KeyTypeTab
-			case tea.KeyCtrlC:
-				keyType = dom.KeyTypeCtrlC
-			case tea.KeyCtrlV:
-				keyType = dom.KeyTypeCtrlV
-			case tea.KeyCtrlX:
-				keyType = dom.KeyTypeCtrlX
-			case tea.KeyCtrlW:
-				keyType = dom.KeyTypeCtrlW
-			case tea.KeyCtrlA:
-				keyType = dom.KeyTypeCtrlA
-			case tea.KeyCtrlE:
-				keyType = dom.KeyTypeCtrlE
-			case tea.KeyCtrlK:
-				keyType = dom.KeyTypeCtrlK
-			}
-			c.dom.DispatchKeyDownEvent(&dom.KeydownEvent{
-				KeyType: keyType,
-				Runes:   msg.Runes,
-				Alt:     msg.Alt,
-				Paste:   msg.Paste,
-			})
+			c.dom.DispatchKeyDownEvent(convertKeyMsg(msg))
+			c.timeKeySequence()
+		}
+	case tea.MouseMsg:
+		log.Logf("Mouse Msg %v at (%d,%d)", msg.Action, msg.X, msg.Y)
+		if c.dom != nil {
+			c.dom.DispatchMouseEvent(convertMouseMsg(msg))
 		}
 	case tea.WindowSizeMsg:
 		log.Logf("window size: %d x %d", msg.Width, msg.Height)
@@ -97,15 +100,174 @@ func (c *CharmApp[T]) Update(msg tea.Msg) {
 	}
 }
 
+// timeKeySequence renders again when the key sequence pending, if any,
+// times out, for KeyHelp to show the bindings of single keys again.
+// A key pressed meanwhile restarts the timeout.
+func (c *CharmApp[T]) timeKeySequence() {
+	if c.dom.PendingKeys() == "" || c.program == nil {
+		if c.keysTimer != nil {
+			c.keysTimer.Stop()
+		}
+		return
+	}
+	if c.keysTimer == nil {
+		c.keysTimer = time.AfterFunc(dom.KeySequenceTimeout, func() {
+			c.program.Send(RerenderMsg{})
+		})
+		return
+	}
+	c.keysTimer.Reset(dom.KeySequenceTimeout)
+}
+
+// SetClipboard sets the clipboard text fields copy to and paste from,
+// for example dom.NewOSC52Clipboard(os.Stdout) to use the one of the terminal.
+// Without one, text is copied to memory and only pasted inside the app.
+func (c *CharmApp[T]) SetClipboard(clipboard dom.Clipboard) {
+	c.clipboard = clipboard
+}
+
+// SetTheme renders the app with the palette and element styles of t,
+// theme.Dark by default. It can be called while the app runs, like from
+// an event handler: the next render uses the new theme.
+func (c *CharmApp[T]) SetTheme(t theme.Theme) {
+	c.renderer.SetTheme(t)
+}
+
+// SetColorProfile renders the app with the colors of p, like
+// termenv.ANSI256, in place of those detected for the terminal,
+// see renderer.DetectColorProfile
+func (c *CharmApp[T]) SetColorProfile(p termenv.Profile) {
+	c.renderer.SetColorProfile(p)
+}
+
 // View renders the current view using rectangle-based rendering
 func (c *CharmApp[T]) Render() string {
+	return c.RenderToRect().String()
+}
+
+// RenderToRect renders the current view like Render, keeping the cells
+// and the layout of the screen
+func (c *CharmApp[T]) RenderToRect() renderer.Rectangle {
 	window := &dom.Window{
 		Width:  c.width,
 		Height: c.height,
 	}
-	c.dom = dom.NewDOM(c.Root(c.State, window), window)
+	prev := c.dom
+	c.dom = c.buildDOM(window, prev)
+	if c.dom.UpdateFocusScopes(prev) && c.program != nil {
+		// a focus scope moved the focus in this DOM, which renders it;
+		// what its OnFocus and OnBlur handlers changed shows in the next frame
+		go c.program.Send(RerenderMsg{})
+	}
 
 	// Use rectangle-based rendering
 	rect := c.renderer.RenderToRect(c.dom.Root, c.width, c.height)
-	return rect.String()
+	// mouse events are hit-tested against what is on screen
+	c.dom.HitTester = rect.Layout
+	return rect
+}
+
+// DOM returns the DOM of the last render, nil before the first one
+func (c *CharmApp[T]) DOM() *dom.DOM {
+	return c.dom
+}
+
+// AddEventListener adds a handler for eventType that sees the events of
+// every node, for global shortcuts, see dom.DOM.AddEventListener
+func (c *CharmApp[T]) AddEventListener(eventType dom.EventType, handler dom.EventHandler, capture bool) dom.ListenerID {
+	return c.listeners.Add(eventType, handler, capture)
+}
+
+// RemoveEventListener removes a handler added by AddEventListener
+func (c *CharmApp[T]) RemoveEventListener(id dom.ListenerID) {
+	c.listeners.Remove(id)
+}
+
+// SetOwnFocus makes the DOM keep track of the focused node, instead of
+// the app through Focused props and OnFocus/OnBlur, see dom.DOM.OwnFocus
+func (c *CharmApp[T]) SetOwnFocus(own bool) {
+	c.ownFocus = own
+}
+
+// FocusedKey returns the Key of the node focused in the last render,
+// for the app to style what it renders around it
+func (c *CharmApp[T]) FocusedKey() string {
+	if c.dom == nil {
+		return ""
+	}
+	return c.dom.FocusedKey()
+}
+
+// Focus focuses the node with the given key rendered last,
+// and reports whether there is one
+func (c *CharmApp[T]) Focus(key string) bool {
+	if c.dom == nil {
+		return false
+	}
+	return c.dom.FocusKey(key)
+}
+
+// KeymapConflicts reports the bindings of the last render that cannot
+// all work, see dom.DOM.KeymapConflicts
+func (c *CharmApp[T]) KeymapConflicts() []error {
+	if c.dom == nil {
+		return nil
+	}
+	return c.dom.KeymapConflicts()
+}
+
+// buildDOM renders the tree of the app into a DOM carrying the state of prev
+func (c *CharmApp[T]) buildDOM(window *dom.Window, prev *dom.DOM) *dom.DOM {
+	// the root is rendered as a component so it can use hooks
+	root := c.react.Render(func() *dom.Node {
+		return c.Root(c.State, window)
+	})
+	d := dom.NewDOM(root, window)
+	d.Clipboard = c.clipboard
+	d.Listeners = &c.listeners
+	d.OwnFocus = c.ownFocus
+	d.Inherit(prev)
+	return d
+}
+
+// convertMouseMsg translates a bubbletea mouse message into a dom.MouseEvent.
+// Mouse messages are only reported when the program is started with
+// tea.WithMouseCellMotion or tea.WithMouseAllMotion.
+func convertMouseMsg(msg tea.MouseMsg) *dom.MouseEvent {
+	event := &dom.MouseEvent{
+		X:     msg.X,
+		Y:     msg.Y,
+		Alt:   msg.Alt,
+		Ctrl:  msg.Ctrl,
+		Shift: msg.Shift,
+	}
+	switch msg.Button {
+	case tea.MouseButtonLeft:
+		event.Button = dom.MouseButtonLeft
+	case tea.MouseButtonMiddle:
+		event.Button = dom.MouseButtonMiddle
+	case tea.MouseButtonRight:
+		event.Button = dom.MouseButtonRight
+	case tea.MouseButtonWheelUp:
+		event.DeltaY = -1
+	case tea.MouseButtonWheelDown:
+		event.DeltaY = 1
+	case tea.MouseButtonWheelLeft:
+		event.DeltaX = -1
+	case tea.MouseButtonWheelRight:
+		event.DeltaX = 1
+	}
+	if tea.MouseEvent(msg).IsWheel() {
+		event.Action = dom.MouseActionWheel
+		return event
+	}
+	switch msg.Action {
+	case tea.MouseActionPress:
+		event.Action = dom.MouseActionPress
+	case tea.MouseActionRelease:
+		event.Action = dom.MouseActionRelease
+	default:
+		event.Action = dom.MouseActionMotion
+	}
+	return event
 }
@@ -0,0 +1,38 @@
+package charm
+
+import (
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+func TestFocusScopeRendersOnce(t *testing.T) {
+	type state struct {
+		open    bool
+		renders int
+	}
+	app := NewCharmApp(&state{}, func(s *state, window *dom.Window) *dom.Node {
+		s.renders++
+		button := dom.Button(dom.ButtonProps{}, dom.Text("open"))
+		button.Key = "button"
+		children := []*dom.Node{button}
+		if s.open {
+			field := dom.Input(dom.InputProps{})
+			field.Key = "field"
+			children = append(children, dom.FocusScope(dom.FocusScopeProps{AutoFocus: true}, field))
+		}
+		return dom.Div(dom.DivProps{}, children...)
+	})
+	app.SetOwnFocus(true)
+	app.RenderToRect()
+
+	app.State.open = true
+	app.State.renders = 0
+	app.RenderToRect()
+	if app.State.renders != 1 {
+		t.Errorf("expected the tree rendered once when the scope focuses, got %d renders", app.State.renders)
+	}
+	if got := app.FocusedKey(); got != "field" {
+		t.Errorf("expected the scope to focus %q, got %q", "field", got)
+	}
+}
@@ -0,0 +1,267 @@
+package charmtest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+	"github.com/xhd2015/go-dom-tui/charm/renderer"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// updateEnv is the environment variable that makes snapshots rewrite their
+// golden files instead of comparing with them, when set to 1. It is not a
+// flag so that the package registers nothing on the flags of the binaries
+// importing it.
+const updateEnv = "CHARMTEST_UPDATE"
+
+// maxCellDiffs is how many differing cells a failed snapshot lists
+const maxCellDiffs = 20
+
+// Snapshot renders node at width x height and compares the cells with the
+// golden file of the test, testdata/<test name>.golden. Running the tests
+// with CHARMTEST_UPDATE=1 writes the golden files instead.
+// Snapshots render in true color whatever the terminal running the tests,
+// so that golden files do not depend on it.
+func Snapshot(tb testing.TB, node *dom.Node, width, height int) {
+	tb.Helper()
+	dom.NewDOM(node, &dom.Window{Width: width, Height: height})
+	r := renderer.NewInteractiveCharmRenderer()
+	r.SetColorProfile(termenv.TrueColor)
+	rect := r.RenderToRect(node, width, height)
+	matchGolden(tb, fit(rect, width, height))
+}
+
+// ExpectSnapshot compares the screen with the golden file of the test,
+// like Snapshot. The styles depend on the color profile of the app, set it
+// with CharmApp.SetColorProfile for golden files that do not depend on the
+// terminal running the tests.
+func (t *Terminal[T]) ExpectSnapshot() {
+	t.tb.Helper()
+	matchGolden(t.tb, t.screen)
+}
+
+// goldenPath returns the golden file of the test, in a directory per test
+// for subtests
+func goldenPath(tb testing.TB) string {
+	return filepath.Join("testdata", filepath.FromSlash(tb.Name())+".golden")
+}
+
+func matchGolden(tb testing.TB, rect renderer.Rectangle) {
+	tb.Helper()
+	path := goldenPath(tb)
+	got := encodeSnapshot(rect)
+	if os.Getenv(updateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			tb.Fatal(err)
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		tb.Fatalf("no golden file %s, run the test with %s=1 to create it, got:\n%s", path, updateEnv, got)
+	}
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if want := string(data); want != got {
+		tb.Errorf("snapshot differs from %s, run the test with %s=1 to accept it\n%s", path, updateEnv, diffSnapshot(decodeSnapshot(want), rect))
+	}
+}
+
+// The golden file holds the text on screen, then a map of the style of
+// every cell, a letter per style explained by a legend:
+//
+//	-- text --
+//	 OK  Cancel
+//	-- styles --
+//	aaaa.bbbbbb
+//	-- legend --
+//	a fg=#ffffff bg=ansi:4 bold
+//	b reverse
+//
+// Cells in the default style are dots. The spaces ending text lines and
+// the dots ending style lines are trimmed.
+const (
+	textHeader   = "-- text --"
+	stylesHeader = "-- styles --"
+	legendHeader = "-- legend --"
+	styleLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// encodeSnapshot writes rect in the golden file format
+func encodeSnapshot(rect renderer.Rectangle) string {
+	var text, styles, legend strings.Builder
+	letters := make(map[renderer.CellStyle]byte)
+	for _, row := range rect.Cells {
+		var line, styleLine strings.Builder
+		for _, cell := range row {
+			line.WriteString(cell.Content)
+			letter := byte('.')
+			if cell.Style != (renderer.CellStyle{}) {
+				var ok bool
+				if letter, ok = letters[cell.Style]; !ok {
+					letter = '?'
+					if len(letters) < len(styleLetters) {
+						letter = styleLetters[len(letters)]
+					}
+					letters[cell.Style] = letter
+					fmt.Fprintf(&legend, "%c %s\n", letter, describeStyle(cell.Style))
+				}
+			}
+			styleLine.WriteByte(letter)
+		}
+		text.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+		styles.WriteString(strings.TrimRight(styleLine.String(), ".") + "\n")
+	}
+	return textHeader + "\n" + text.String() + stylesHeader + "\n" + styles.String() + legendHeader + "\n" + legend.String()
+}
+
+// describeStyle writes a cell style like "fg=#ff0000 bg=ansi:4 bold",
+// "default" for the default style
+func describeStyle(style renderer.CellStyle) string {
+	var parts []string
+	if c := describeColor(style.Fg); c != "" {
+		parts = append(parts, "fg="+c)
+	}
+	if c := describeColor(style.Bg); c != "" {
+		parts = append(parts, "bg="+c)
+	}
+	for _, attr := range []struct {
+		attr renderer.Attr
+		name string
+	}{
+		{renderer.AttrBold, "bold"},
+		{renderer.AttrFaint, "faint"},
+		{renderer.AttrItalic, "italic"},
+		{renderer.AttrUnderline, "underline"},
+		{renderer.AttrBlink, "blink"},
+		{renderer.AttrReverse, "reverse"},
+		{renderer.AttrStrikethrough, "strikethrough"},
+	} {
+		if style.Attrs&attr.attr != 0 {
+			parts = append(parts, attr.name)
+		}
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, " ")
+}
+
+func describeColor(c renderer.Color) string {
+	switch c.Kind {
+	case renderer.ColorANSI:
+		return fmt.Sprintf("ansi:%d", c.Value)
+	case renderer.ColorANSI256:
+		return fmt.Sprintf("256:%d", c.Value)
+	case renderer.ColorRGB:
+		return fmt.Sprintf("#%06x", c.Value)
+	}
+	return ""
+}
+
+// snapshotCell is a cell as read back from a golden file
+type snapshotCell struct {
+	content string
+	style   string // see describeStyle
+}
+
+// decodeSnapshot reads the cells of a golden file, as far as it is well formed
+func decodeSnapshot(data string) [][]snapshotCell {
+	var text, styles []string
+	legend := map[byte]string{'.': "default"}
+	section := ""
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+		switch line {
+		case textHeader, stylesHeader, legendHeader:
+			section = line
+			continue
+		}
+		switch section {
+		case textHeader:
+			text = append(text, line)
+		case stylesHeader:
+			styles = append(styles, line)
+		case legendHeader:
+			if len(line) > 2 {
+				legend[line[0]] = line[2:]
+			}
+		}
+	}
+
+	rows := make([][]snapshotCell, len(text))
+	for y, line := range text {
+		cells := renderer.NewRectangle(line).Cells[0]
+		styleLine := ""
+		if y < len(styles) {
+			styleLine = styles[y]
+		}
+		for x := 0; x < max(len(cells), len(styleLine)); x++ {
+			cell := snapshotCell{content: " ", style: "default"}
+			if x < len(cells) {
+				cell.content = cells[x].Content
+			}
+			if x < len(styleLine) {
+				cell.style = legend[styleLine[x]]
+			}
+			rows[y] = append(rows[y], cell)
+		}
+	}
+	return rows
+}
+
+// diffSnapshot shows the text expected and rendered, and lists the cells
+// that differ in content or style
+func diffSnapshot(want [][]snapshotCell, got renderer.Rectangle) string {
+	var b strings.Builder
+	var wantText strings.Builder
+	for _, row := range want {
+		for _, cell := range row {
+			wantText.WriteString(cell.content)
+		}
+		wantText.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "want:\n%sgot:\n%s\n", wantText.String(), renderer.StripColor(got.String()))
+
+	diffs := 0
+	for y := 0; y < max(len(want), len(got.Cells)); y++ {
+		var wantRow []snapshotCell
+		var gotRow []renderer.Cell
+		if y < len(want) {
+			wantRow = want[y]
+		}
+		if y < len(got.Cells) {
+			gotRow = got.Cells[y]
+		}
+		for x := 0; x < max(len(wantRow), len(gotRow)); x++ {
+			w := snapshotCell{content: " ", style: "default"}
+			if x < len(wantRow) {
+				w = wantRow[x]
+			}
+			g := snapshotCell{content: " ", style: "default"}
+			if x < len(gotRow) {
+				g = snapshotCell{content: gotRow[x].Content, style: describeStyle(gotRow[x].Style)}
+			}
+			if w == g {
+				continue
+			}
+			diffs++
+			if diffs <= maxCellDiffs {
+				fmt.Fprintf(&b, "cell (%d, %d): want %q %s, got %q %s\n", x, y, w.content, w.style, g.content, g.style)
+			}
+		}
+	}
+	if diffs > maxCellDiffs {
+		fmt.Fprintf(&b, "... and %d more cells\n", diffs-maxCellDiffs)
+	}
+	return b.String()
+}
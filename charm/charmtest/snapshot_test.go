@@ -0,0 +1,40 @@
+package charmtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/charm/renderer"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	want := renderer.NewRectangle("\x1b[1mab\x1b[0m你\n  \x1b[7m \x1b[0m")
+	golden := encodeSnapshot(want)
+	expected := `-- text --
+ab你
+
+-- styles --
+aa
+..b
+-- legend --
+a bold
+b reverse
+`
+	if golden != expected {
+		t.Fatalf("expected golden file:\n%s\ngot:\n%s", expected, golden)
+	}
+
+	got := renderer.NewRectangle("\x1b[1maB\x1b[0m你\n  ")
+	diff := diffSnapshot(decodeSnapshot(golden), got)
+	for _, line := range []string{
+		`cell (1, 0): want "b" bold, got "B" bold`,
+		`cell (2, 1): want " " reverse, got " " default`,
+	} {
+		if !strings.Contains(diff, line) {
+			t.Errorf("expected the diff to contain %q, got:\n%s", line, diff)
+		}
+	}
+	if n := strings.Count(diff, "cell ("); n != 2 {
+		t.Errorf("expected 2 cells to differ, got %d:\n%s", n, diff)
+	}
+}
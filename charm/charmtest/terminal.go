@@ -0,0 +1,234 @@
+// Package charmtest runs a CharmApp in a virtual terminal, for tests that
+// send keys to an app and check what is on screen and what has the focus.
+package charmtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xhd2015/go-dom-tui/charm"
+	"github.com/xhd2015/go-dom-tui/charm/renderer"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// Terminal is a virtual terminal of a fixed size running an app.
+// Like a bubbletea program, it renders the app again after every message.
+type Terminal[T any] struct {
+	App *charm.CharmApp[T]
+
+	tb     testing.TB
+	width  int
+	height int
+	screen renderer.Rectangle
+}
+
+// New starts app in a terminal of width x height. Errors, like keys
+// that do not parse, and failed expectations are reported to tb.
+func New[T any](tb testing.TB, app *charm.CharmApp[T], width, height int) *Terminal[T] {
+	t := &Terminal[T]{App: app, tb: tb, width: width, height: height}
+	t.render()
+	t.Resize(width, height)
+	return t
+}
+
+// Send sends msg to the app and renders it again
+func (t *Terminal[T]) Send(msg tea.Msg) {
+	t.App.Update(msg)
+	t.render()
+}
+
+// render renders the app, keeping what fits on screen like a terminal
+func (t *Terminal[T]) render() {
+	t.screen = fit(t.App.RenderToRect(), t.width, t.height)
+}
+
+// fit returns the part of rect drawn on a screen of width x height,
+// padded with blank cells
+func fit(rect renderer.Rectangle, width, height int) renderer.Rectangle {
+	screen := renderer.NewEmptyRectangle(width, height)
+	for y := 0; y < height && y < len(rect.Cells); y++ {
+		row := screen.Cells[y]
+		copy(row, rect.Cells[y])
+		if len(row) > 0 && row[len(row)-1].Width > 1 {
+			// half of a wide grapheme does not fit
+			row[len(row)-1] = renderer.Cell{Content: " ", Width: 1}
+		}
+	}
+	screen.Layout = rect.Layout
+	return screen
+}
+
+// Resize resizes the terminal, sending the app a tea.WindowSizeMsg
+func (t *Terminal[T]) Resize(width, height int) {
+	t.width, t.height = width, height
+	t.Send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// Keys presses the keys written in keys, see ParseKeys, one at a time
+func (t *Terminal[T]) Keys(keys string) {
+	t.tb.Helper()
+	parsed, err := ParseKeys(keys)
+	if err != nil {
+		t.tb.Fatal(err)
+	}
+	for _, key := range parsed {
+		msg, ok := charm.KeyMsg(key)
+		if !ok {
+			t.tb.Fatalf("key %q cannot be sent by a terminal", key)
+		}
+		t.Send(msg)
+	}
+}
+
+// ParseKeys parses the compact syntax of Terminal.Keys: text is typed
+// as is, one key per character, and named keys are written in the form
+// dom.ParseKey accepts between angle brackets, like
+//
+//	buy milk<enter><ctrl+c>
+//
+// "<<>" types a "<".
+func ParseKeys(s string) ([]dom.Key, error) {
+	var keys []dom.Key
+	for i := 0; i < len(s); {
+		if s[i] == '<' {
+			end := -1
+			if i+2 <= len(s) {
+				end = strings.IndexByte(s[i+2:], '>')
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("invalid keys %q: missing > after offset %d", s, i)
+			}
+			end += i + 2
+			key, err := dom.ParseKey(s[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			i = end + 1
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == ' ' {
+			keys = append(keys, dom.Key{Code: "space"})
+		} else {
+			keys = append(keys, dom.Key{Code: string(r)})
+		}
+		i += size
+	}
+	return keys, nil
+}
+
+// Screen returns the text on screen without styles, with the spaces
+// ending the lines and the empty lines ending the screen trimmed
+func (t *Terminal[T]) Screen() string {
+	lines := strings.Split(renderer.StripColor(t.screen.String()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// Cells returns the styled cells on screen, a row per line
+func (t *Terminal[T]) Cells() [][]renderer.Cell {
+	return t.screen.Cells
+}
+
+// Cell returns the cell at column x of line y, a blank one outside the screen
+func (t *Terminal[T]) Cell(x, y int) renderer.Cell {
+	if y < 0 || y >= len(t.screen.Cells) || x < 0 || x >= len(t.screen.Cells[y]) {
+		return renderer.Cell{Content: " ", Width: 1}
+	}
+	return t.screen.Cells[y][x]
+}
+
+// FindByText returns the first node in document order holding text in
+// its own text, like the text node inside a button, or nil
+func (t *Terminal[T]) FindByText(text string) *dom.Node {
+	return t.find(func(node *dom.Node) bool {
+		return strings.Contains(node.Text, text)
+	})
+}
+
+// FindByKey returns the first node in document order with the given Key, or nil
+func (t *Terminal[T]) FindByKey(key string) *dom.Node {
+	return t.find(func(node *dom.Node) bool {
+		return node.Key == key
+	})
+}
+
+func (t *Terminal[T]) find(match func(node *dom.Node) bool) *dom.Node {
+	d := t.App.DOM()
+	if d == nil {
+		return nil
+	}
+	var found *dom.Node
+	var walk func(node *dom.Node)
+	walk = func(node *dom.Node) {
+		if node == nil || found != nil {
+			return
+		}
+		if match(node) {
+			found = node
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(d.Root)
+	return found
+}
+
+// BoundsOf returns where node is on screen, and false if it is not
+func (t *Terminal[T]) BoundsOf(node *dom.Node) (dom.Rect, bool) {
+	if t.screen.Layout == nil || node == nil {
+		return dom.Rect{}, false
+	}
+	return t.screen.Layout.BoundsOf(node)
+}
+
+// Focused returns the focused node, or nil
+func (t *Terminal[T]) Focused() *dom.Node {
+	if d := t.App.DOM(); d != nil {
+		return d.FocusedNode
+	}
+	return nil
+}
+
+// ExpectFocus reports an error unless the node with the given Key has the
+// focus, or nothing has it for an empty key
+func (t *Terminal[T]) ExpectFocus(key string) {
+	t.tb.Helper()
+	focused := t.Focused()
+	switch {
+	case focused == nil && key != "":
+		t.tb.Errorf("expected %q focused, nothing is", key)
+	case focused != nil && focused.Key != key:
+		t.tb.Errorf("expected %q focused, got %s %q at %s", key, focused.Type, focused.Key, focused.Path())
+	}
+}
+
+// ExpectScreen reports an error unless Screen is expected, ignoring the
+// spaces ending its lines and the empty lines ending it
+func (t *Terminal[T]) ExpectScreen(expected string) {
+	t.tb.Helper()
+	lines := strings.Split(expected, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	expected = strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	if got := t.Screen(); got != expected {
+		t.tb.Errorf("expected screen:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+// ExpectText reports an error unless text is on screen
+func (t *Terminal[T]) ExpectText(text string) {
+	t.tb.Helper()
+	if !strings.Contains(t.Screen(), text) {
+		t.tb.Errorf("expected %q on screen, got:\n%s", text, t.Screen())
+	}
+}
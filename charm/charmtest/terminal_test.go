@@ -0,0 +1,108 @@
+package charmtest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/charm"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// todoState is a todo list with an input adding items
+type todoState struct {
+	items  []string
+	value  string
+	cursor int
+}
+
+func todoApp(state *todoState, window *dom.Window) *dom.Node {
+	input := dom.Input(dom.InputProps{
+		Placeholder:    "new item",
+		Value:          state.value,
+		CursorPosition: state.cursor,
+		OnChange:       func(s string) { state.value = s },
+		OnCursorMove:   func(p int) { state.cursor = p },
+		OnKeyDown: func(e *dom.DOMEvent) {
+			if e.KeydownEvent.KeyType == dom.KeyTypeEnter && state.value != "" {
+				state.items = append(state.items, state.value)
+				state.value, state.cursor = "", 0
+			}
+		},
+	})
+	input.Key = "input"
+	children := []*dom.Node{input}
+	for _, item := range state.items {
+		li := dom.Div(dom.DivProps{TabIndex: dom.TabIndex(0)}, dom.Text("- "+item))
+		li.Key = item
+		children = append(children, li)
+	}
+	return dom.Div(dom.DivProps{}, children...)
+}
+
+func newTodo(t *testing.T) *Terminal[todoState] {
+	app := charm.NewCharmApp(&todoState{}, todoApp)
+	app.SetOwnFocus(true)
+	term := New(t, app, 20, 5)
+	app.Focus("input")
+	return term
+}
+
+func TestTerminal(t *testing.T) {
+	term := newTodo(t)
+	term.Keys("buy milk<enter>walk<enter>")
+	term.ExpectScreen(`
+ ┌──────────────────
+ │  >  new item
+ └──────────────────
+- buy milk
+- walk
+`[1:])
+	term.ExpectFocus("input")
+
+	term.Keys("<tab>")
+	term.ExpectFocus("buy milk")
+	term.Keys("<down>")
+	term.ExpectFocus("walk")
+
+	node := term.FindByText("walk")
+	if bounds, ok := term.BoundsOf(node); !ok || bounds.Y != 4 {
+		t.Errorf("expected walk on line 4, got %+v %v", bounds, ok)
+	}
+	if term.FindByKey("buy milk") == nil || term.FindByKey("bread") != nil {
+		t.Errorf("expected to find the nodes by key")
+	}
+	if got := term.Cell(2, 3).Content; got != "b" {
+		t.Errorf("expected cell (2, 3) to be %q, got %q", "b", got)
+	}
+}
+
+func TestTerminalResize(t *testing.T) {
+	term := newTodo(t)
+	term.Keys("a long item to add<enter>")
+	term.ExpectText("- a long item to add")
+	term.Resize(10, 5)
+	if width := len(term.Cells()[0]); width != 10 {
+		t.Errorf("expected a screen 10 cells wide, got %d", width)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("a <ctrl+c><<><shift+tab>>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, key := range keys {
+		got = append(got, key.String())
+	}
+	expected := []string{"a", "space", "ctrl+c", "<", "shift+tab", ">"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	for _, bad := range []string{"<enter", "<>", "<hyper+x>"} {
+		if _, err := ParseKeys(bad); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
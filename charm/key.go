@@ -0,0 +1,149 @@
+package charm
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// teaKeys maps the key types of bubbletea to the keys they are.
+// Terminals send tab, enter, esc and backspace as ctrl+i, ctrl+m, ctrl+[
+// and ctrl+?, so these are reported by their names.
+var teaKeys = map[tea.KeyType]dom.Key{
+	tea.KeyEnter:     {Code: "enter"},
+	tea.KeyBackspace: {Code: "backspace"},
+	tea.KeyDelete:    {Code: "delete"},
+	tea.KeyInsert:    {Code: "insert"},
+	tea.KeyTab:       {Code: "tab"},
+	tea.KeyShiftTab:  {Code: "tab", Shift: true},
+	tea.KeyEscape:    {Code: "esc"},
+	tea.KeySpace:     {Code: "space"},
+
+	tea.KeyUp:             {Code: "up"},
+	tea.KeyDown:           {Code: "down"},
+	tea.KeyLeft:           {Code: "left"},
+	tea.KeyRight:          {Code: "right"},
+	tea.KeyHome:           {Code: "home"},
+	tea.KeyEnd:            {Code: "end"},
+	tea.KeyPgUp:           {Code: "pgup"},
+	tea.KeyPgDown:         {Code: "pgdown"},
+	tea.KeyShiftUp:        {Code: "up", Shift: true},
+	tea.KeyShiftDown:      {Code: "down", Shift: true},
+	tea.KeyShiftLeft:      {Code: "left", Shift: true},
+	tea.KeyShiftRight:     {Code: "right", Shift: true},
+	tea.KeyShiftHome:      {Code: "home", Shift: true},
+	tea.KeyShiftEnd:       {Code: "end", Shift: true},
+	tea.KeyCtrlUp:         {Code: "up", Ctrl: true},
+	tea.KeyCtrlDown:       {Code: "down", Ctrl: true},
+	tea.KeyCtrlLeft:       {Code: "left", Ctrl: true},
+	tea.KeyCtrlRight:      {Code: "right", Ctrl: true},
+	tea.KeyCtrlHome:       {Code: "home", Ctrl: true},
+	tea.KeyCtrlEnd:        {Code: "end", Ctrl: true},
+	tea.KeyCtrlPgUp:       {Code: "pgup", Ctrl: true},
+	tea.KeyCtrlPgDown:     {Code: "pgdown", Ctrl: true},
+	tea.KeyCtrlShiftUp:    {Code: "up", Ctrl: true, Shift: true},
+	tea.KeyCtrlShiftDown:  {Code: "down", Ctrl: true, Shift: true},
+	tea.KeyCtrlShiftLeft:  {Code: "left", Ctrl: true, Shift: true},
+	tea.KeyCtrlShiftRight: {Code: "right", Ctrl: true, Shift: true},
+	tea.KeyCtrlShiftHome:  {Code: "home", Ctrl: true, Shift: true},
+	tea.KeyCtrlShiftEnd:   {Code: "end", Ctrl: true, Shift: true},
+
+	tea.KeyF1:  {Code: "f1"},
+	tea.KeyF2:  {Code: "f2"},
+	tea.KeyF3:  {Code: "f3"},
+	tea.KeyF4:  {Code: "f4"},
+	tea.KeyF5:  {Code: "f5"},
+	tea.KeyF6:  {Code: "f6"},
+	tea.KeyF7:  {Code: "f7"},
+	tea.KeyF8:  {Code: "f8"},
+	tea.KeyF9:  {Code: "f9"},
+	tea.KeyF10: {Code: "f10"},
+	tea.KeyF11: {Code: "f11"},
+	tea.KeyF12: {Code: "f12"},
+	tea.KeyF13: {Code: "f13"},
+	tea.KeyF14: {Code: "f14"},
+	tea.KeyF15: {Code: "f15"},
+	tea.KeyF16: {Code: "f16"},
+	tea.KeyF17: {Code: "f17"},
+	tea.KeyF18: {Code: "f18"},
+	tea.KeyF19: {Code: "f19"},
+	tea.KeyF20: {Code: "f20"},
+
+	tea.KeyCtrlAt:           {Code: "@", Ctrl: true},
+	tea.KeyCtrlA:            {Code: "a", Ctrl: true},
+	tea.KeyCtrlB:            {Code: "b", Ctrl: true},
+	tea.KeyCtrlC:            {Code: "c", Ctrl: true},
+	tea.KeyCtrlD:            {Code: "d", Ctrl: true},
+	tea.KeyCtrlE:            {Code: "e", Ctrl: true},
+	tea.KeyCtrlF:            {Code: "f", Ctrl: true},
+	tea.KeyCtrlG:            {Code: "g", Ctrl: true},
+	tea.KeyCtrlH:            {Code: "h", Ctrl: true},
+	tea.KeyCtrlJ:            {Code: "j", Ctrl: true},
+	tea.KeyCtrlK:            {Code: "k", Ctrl: true},
+	tea.KeyCtrlL:            {Code: "l", Ctrl: true},
+	tea.KeyCtrlN:            {Code: "n", Ctrl: true},
+	tea.KeyCtrlO:            {Code: "o", Ctrl: true},
+	tea.KeyCtrlP:            {Code: "p", Ctrl: true},
+	tea.KeyCtrlQ:            {Code: "q", Ctrl: true},
+	tea.KeyCtrlR:            {Code: "r", Ctrl: true},
+	tea.KeyCtrlS:            {Code: "s", Ctrl: true},
+	tea.KeyCtrlT:            {Code: "t", Ctrl: true},
+	tea.KeyCtrlU:            {Code: "u", Ctrl: true},
+	tea.KeyCtrlV:            {Code: "v", Ctrl: true},
+	tea.KeyCtrlW:            {Code: "w", Ctrl: true},
+	tea.KeyCtrlX:            {Code: "x", Ctrl: true},
+	tea.KeyCtrlY:            {Code: "y", Ctrl: true},
+	tea.KeyCtrlZ:            {Code: "z", Ctrl: true},
+	tea.KeyCtrlBackslash:    {Code: "\\", Ctrl: true},
+	tea.KeyCtrlCloseBracket: {Code: "]", Ctrl: true},
+	tea.KeyCtrlCaret:        {Code: "^", Ctrl: true},
+	tea.KeyCtrlUnderscore:   {Code: "_", Ctrl: true},
+}
+
+// convertKeyMsg translates a bubbletea key message into a dom.KeydownEvent
+func convertKeyMsg(msg tea.KeyMsg) *dom.KeydownEvent {
+	var key dom.Key
+	if msg.Type == tea.KeyRunes {
+		key = dom.Key{Code: string(msg.Runes)}
+	} else if k, ok := teaKeys[msg.Type]; ok {
+		key = k
+	} else {
+		// a key added to bubbletea after this table, keep its name
+		key = dom.Key{Code: msg.Type.String()}
+	}
+	key.Alt = msg.Alt
+
+	event := dom.NewKeydownEvent(key)
+	if len(msg.Runes) > 0 {
+		event.Runes = msg.Runes
+	}
+	event.Paste = msg.Paste
+	return event
+}
+
+// teaKeyTypes maps the canonical form of keys back to the key types of bubbletea
+var teaKeyTypes = func() map[string]tea.KeyType {
+	m := make(map[string]tea.KeyType, len(teaKeys))
+	for keyType, key := range teaKeys {
+		m[key.String()] = keyType
+	}
+	return m
+}()
+
+// KeyMsg returns the bubbletea key message a terminal sends for key,
+// the reverse of what the app reads from it. ok is false for the keys
+// bubbletea cannot report, like meta or shift with most keys.
+func KeyMsg(key dom.Key) (msg tea.KeyMsg, ok bool) {
+	alt := key.Alt
+	key.Alt = false
+	switch {
+	case key.IsRunes() && !key.Ctrl && !key.Shift && !key.Meta:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key.Code), Alt: alt}, true
+	case key == dom.Key{Code: "space"}:
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}, Alt: alt}, true
+	}
+	keyType, ok := teaKeyTypes[key.String()]
+	if !ok {
+		return tea.KeyMsg{}, false
+	}
+	return tea.KeyMsg{Type: keyType, Alt: alt}, true
+}
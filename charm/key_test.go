@@ -0,0 +1,61 @@
+package charm
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+func TestConvertKeyMsg(t *testing.T) {
+	tests := []struct {
+		msg     tea.KeyMsg
+		key     string
+		keyType dom.KeyType
+		runes   string
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, "x", "", "x"},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f"), Alt: true}, "alt+f", "", "f"},
+		{tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}, "space", dom.KeyTypeSpace, " "},
+		{tea.KeyMsg{Type: tea.KeyEnter}, "enter", dom.KeyTypeEnter, ""},
+		{tea.KeyMsg{Type: tea.KeyShiftTab}, "shift+tab", dom.KeyTypeShiftTab, ""},
+		{tea.KeyMsg{Type: tea.KeyCtrlD}, "ctrl+d", "ctrl+d", ""},
+		{tea.KeyMsg{Type: tea.KeyCtrlShiftUp}, "ctrl+shift+up", "ctrl+shift+up", ""},
+		{tea.KeyMsg{Type: tea.KeyUp, Alt: true}, "alt+up", "alt+up", ""},
+		{tea.KeyMsg{Type: tea.KeyF5}, "f5", dom.KeyTypeF5, ""},
+		{tea.KeyMsg{Type: tea.KeyCtrlPgDown}, "ctrl+pgdown", "ctrl+pgdown", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			e := convertKeyMsg(tt.msg)
+			if e.Key.String() != tt.key || e.KeyType != tt.keyType || string(e.Runes) != tt.runes {
+				t.Errorf("expected %q %q %q, got %q %q %q", tt.key, tt.keyType, tt.runes, e.Key.String(), e.KeyType, string(e.Runes))
+			}
+		})
+	}
+}
+
+func TestConvertKeyMsgComplete(t *testing.T) {
+	var types []tea.KeyType
+	for k := tea.KeyCtrlAt; k <= tea.KeyCtrlUnderscore; k++ {
+		types = append(types, k)
+	}
+	types = append(types, tea.KeyBackspace)
+	for k := tea.KeyUp; k >= tea.KeyF20; k-- {
+		types = append(types, k)
+	}
+	for _, k := range types {
+		e := convertKeyMsg(tea.KeyMsg{Type: k})
+		if _, ok := teaKeys[k]; !ok {
+			t.Errorf("%s is missing from the key table", k)
+			continue
+		}
+		parsed, err := dom.ParseKey(e.Key.String())
+		if err != nil || parsed != e.Key {
+			t.Errorf("expected %s to round trip through %q, got %+v %v", k, e.Key.String(), parsed, err)
+		}
+		if msg, ok := KeyMsg(e.Key); !ok || msg.Type != k {
+			t.Errorf("expected KeyMsg(%q) to be %s, got %s %v", e.Key.String(), k, msg.Type, ok)
+		}
+	}
+}
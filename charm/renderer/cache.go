@@ -0,0 +1,171 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// renderCache keeps the Rectangle of every node marked with dom.Memo
+// rendered in the last pass, so that subtrees that did not change between
+// frames are not rendered through lipgloss again.
+//
+// Entries are keyed by the node's position in the tree (dom.Node.Path) and
+// the space it was rendered into. An entry is only reused when the node has
+// the same memo deps and the focus the DOM owns is at the same place in the
+// subtree. Nodes without memo deps are always rendered, so their subtrees are
+// never walked by the cache.
+type renderCache struct {
+	entries map[cacheKey]*cacheEntry
+	focused string // path of the focused node of the current pass, if any
+	pass    int
+	depth   int // nesting of RenderToRect calls
+
+	hits   int
+	misses int
+}
+
+type cacheKey struct {
+	path   string
+	width  int
+	height int
+	fill   fillAxes
+}
+
+type cacheEntry struct {
+	deps    []interface{}
+	focused string    // path of the focused node inside the subtree, if any
+	node    *dom.Node // root of the subtree the rectangle was rendered from
+	rect    Rectangle
+	pass    int // last pass the entry was used in
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{
+		entries: make(map[cacheKey]*cacheEntry),
+	}
+}
+
+// begin starts a render pass of the tree of root
+func (c *renderCache) begin(root *dom.Node) {
+	c.depth++
+	if c.depth > 1 {
+		return
+	}
+	c.pass++
+	c.focused = ""
+	if focused := root.FindFocused(); focused != nil {
+		c.focused = focused.Path()
+	}
+}
+
+// end finishes a render pass, dropping entries that were not used in it
+func (c *renderCache) end() {
+	c.depth--
+	if c.depth > 0 {
+		return
+	}
+	for key, entry := range c.entries {
+		if entry.pass != c.pass {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// get returns the cached rectangle of vnode, with its layout boxes
+// pointing to the nodes of vnode's subtree
+func (c *renderCache) get(vnode *dom.Node, width, height int, fill fillAxes) (Rectangle, bool) {
+	if !memoized(vnode) {
+		return Rectangle{}, false
+	}
+	entry := c.entries[cacheKey{path: vnode.Path(), width: width, height: height, fill: fill}]
+	if entry == nil || entry.focused != c.focusedIn(vnode) || !sameDeps(entry.deps, vnode.Memo) {
+		c.misses++
+		return Rectangle{}, false
+	}
+	c.hits++
+	entry.pass = c.pass
+
+	// rectangles are never modified once rendered, so the cells are shared
+	rect := entry.rect
+	if entry.node != vnode {
+		nodes := make(map[*dom.Node]*dom.Node)
+		mapNodes(entry.node, vnode, nodes)
+		rect.boxes = remapBoxes(entry.rect.boxes, nodes)
+		entry.node = vnode
+		entry.rect.boxes = rect.boxes
+	}
+	return rect, true
+}
+
+// put stores the rectangle rendered for vnode
+func (c *renderCache) put(vnode *dom.Node, width, height int, fill fillAxes, rect Rectangle) {
+	if !memoized(vnode) {
+		return
+	}
+	c.entries[cacheKey{path: vnode.Path(), width: width, height: height, fill: fill}] = &cacheEntry{
+		deps:    vnode.Memo,
+		focused: c.focusedIn(vnode),
+		node:    vnode,
+		rect:    rect,
+		pass:    c.pass,
+	}
+}
+
+// memoized reports whether the output of vnode can be cached: it is marked
+// with dom.Memo, and has a path that tells it apart from the other nodes,
+// which only the root of a tree set up by dom.NewDOM lacks
+func memoized(vnode *dom.Node) bool {
+	return vnode.Memo != nil && vnode.Parent != nil
+}
+
+// focusedIn returns the path of the focused node if it is inside the
+// subtree of vnode, or ""
+func (c *renderCache) focusedIn(vnode *dom.Node) string {
+	path := vnode.Path()
+	if c.focused == path || strings.HasPrefix(c.focused, path+"/") {
+		return c.focused
+	}
+	return ""
+}
+
+// sameDeps compares memo deps like React.UseEffect compares its deps
+func sameDeps(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mapNodes pairs the nodes of two subtrees with the same memo deps
+func mapNodes(from, to *dom.Node, nodes map[*dom.Node]*dom.Node) {
+	if from == nil || to == nil {
+		return
+	}
+	nodes[from] = to
+	for i := 0; i < len(from.Children) && i < len(to.Children); i++ {
+		mapNodes(from.Children[i], to.Children[i], nodes)
+	}
+}
+
+// remapBoxes copies boxes, pointing them to the mapped nodes
+func remapBoxes(boxes []*layoutBox, nodes map[*dom.Node]*dom.Node) []*layoutBox {
+	if len(boxes) == 0 {
+		return nil
+	}
+	remapped := make([]*layoutBox, len(boxes))
+	for i, box := range boxes {
+		cp := *box
+		if node, ok := nodes[box.node]; ok {
+			cp.node = node
+		}
+		cp.children = remapBoxes(box.children, nodes)
+		remapped[i] = &cp
+	}
+	return remapped
+}
@@ -0,0 +1,147 @@
+package renderer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+// logViewer builds a list of rows, the way an app rebuilds its tree every
+// frame. Each row only depends on its index and whether it is selected.
+func logViewer(rows int, selected int) *dom.Node {
+	children := make([]*dom.Node, rows)
+	for i := range children {
+		children[i] = dom.Memo(dom.Li(dom.ListItemProps{
+			Selected: i == selected,
+			OnFocus:  func() {},
+		},
+			dom.Text(fmt.Sprintf("%04d ", i), styles.Style{Color: "8"}),
+			dom.Text("INFO ", styles.Style{Color: "10", Bold: true}),
+			dom.Text(fmt.Sprintf("request served in %dms", i%97)),
+		), i, i == selected)
+	}
+	return dom.Div(dom.DivProps{},
+		dom.Memo(dom.H1(dom.DivProps{}, dom.Text("Logs"))),
+		dom.Ul(dom.DivProps{}, children...),
+	)
+}
+
+func renderFrame(cr *InteractiveCharmRenderer, root *dom.Node, width, height int) Rectangle {
+	dom.NewDOM(root, &dom.Window{Width: width, Height: height})
+	return cr.RenderToRect(root, width, height)
+}
+
+func TestRenderCacheReusesUnchangedSubtrees(t *testing.T) {
+	cr := NewInteractiveCharmRenderer()
+	renderFrame(cr, logViewer(20, 0), 60, 30)
+
+	cr.cache.hits, cr.cache.misses = 0, 0
+	root := logViewer(20, 3)
+	rect := renderFrame(cr, root, 60, 30)
+
+	fresh := NewInteractiveCharmRenderer().RenderToRect(logViewer(20, 3), 60, 30)
+	if rect.String() != fresh.String() {
+		t.Fatalf("cached output differs from a fresh render:\n%s\nvs\n%s", rect.String(), fresh.String())
+	}
+	// the title and the 18 rows whose selection did not change are reused
+	if cr.cache.hits != 19 {
+		t.Errorf("expected 19 cache hits, got %d", cr.cache.hits)
+	}
+
+	// the layout refers to the nodes of the new tree
+	row := root.Children[1].Children[10]
+	bounds, ok := rect.Layout.BoundsOf(row)
+	if !ok {
+		t.Fatalf("expected reused row to be in the layout")
+	}
+	if got := rect.Layout.NodeAt(bounds.X, bounds.Y); got != row && got != row.Children[0] {
+		t.Errorf("expected NodeAt to hit the new row, got %v", got)
+	}
+}
+
+func TestRenderCacheKeyedBySize(t *testing.T) {
+	cr := NewInteractiveCharmRenderer()
+	wide := renderFrame(cr, logViewer(3, 0), 60, 10)
+	narrow := renderFrame(cr, logViewer(3, 0), 60, 2)
+
+	if wide.Height == narrow.Height {
+		t.Errorf("expected rendering into less height to produce a different rectangle")
+	}
+}
+
+func TestRenderCacheSeesOwnedFocus(t *testing.T) {
+	form := func() *dom.Node {
+		return dom.Div(dom.DivProps{},
+			dom.Memo(dom.TextArea(dom.TextAreaProps{Value: "a"})),
+			dom.Memo(dom.TextArea(dom.TextAreaProps{Value: "b"})),
+		)
+	}
+	cr := NewInteractiveCharmRenderer()
+	root := form()
+	d := dom.NewDOM(root, &dom.Window{})
+	d.OwnFocus = true
+	before := cr.RenderToRect(root, 20, 4).String()
+
+	d.MoveTabFocus(1)
+	after := cr.RenderToRect(root, 20, 4).String()
+	if after == before {
+		t.Errorf("expected the focused text area to render its cursor")
+	}
+	if fresh := NewInteractiveCharmRenderer().RenderToRect(root, 20, 4).String(); after != fresh {
+		t.Errorf("cached output differs from a fresh render:\n%s\nvs\n%s", after, fresh)
+	}
+}
+
+func TestRenderCacheComparesMemoDeps(t *testing.T) {
+	label := func(text string, deps ...interface{}) *dom.Node {
+		return dom.Div(dom.DivProps{}, dom.Memo(dom.Text(text), deps...))
+	}
+	cr := NewInteractiveCharmRenderer()
+	renderFrame(cr, label("a", 1), 10, 1)
+
+	// the deps say the output did not change, so it is reused as is
+	if got := StripColor(renderFrame(cr, label("b", 1), 10, 1).String()); got != "a" {
+		t.Errorf("expected the output of the last frame for the same deps, got %q", got)
+	}
+	if got := StripColor(renderFrame(cr, label("b", 2), 10, 1).String()); got != "b" {
+		t.Errorf("expected a new output for new deps, got %q", got)
+	}
+}
+
+func TestRenderCacheSkipsNodesWithoutMemo(t *testing.T) {
+	cr := NewInteractiveCharmRenderer()
+	renderFrame(cr, dom.Div(dom.DivProps{}, dom.Text("a")), 10, 1)
+	cr.cache.hits, cr.cache.misses = 0, 0
+	renderFrame(cr, dom.Div(dom.DivProps{}, dom.Text("a")), 10, 1)
+
+	if cr.cache.hits != 0 || cr.cache.misses != 0 || len(cr.cache.entries) != 0 {
+		t.Errorf("expected nodes without memo deps to bypass the cache")
+	}
+}
+
+func benchmarkLogViewer(b *testing.B, cached bool) {
+	cr := NewInteractiveCharmRenderer()
+	renderFrame(cr, logViewer(2000, 0), 100, 2100)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// building the tree is the app's cost, not the renderer's
+		b.StopTimer()
+		if !cached {
+			cr = NewInteractiveCharmRenderer()
+		}
+		// moving the selection changes two rows per frame
+		root := logViewer(2000, i%2000)
+		dom.NewDOM(root, &dom.Window{Width: 100, Height: 2100})
+		b.StartTimer()
+
+		cr.RenderToRect(root, 100, 2100)
+	}
+}
+
+func BenchmarkRenderLogViewer(b *testing.B) {
+	b.Run("uncached", func(b *testing.B) { benchmarkLogViewer(b, false) })
+	b.Run("cached", func(b *testing.B) { benchmarkLogViewer(b, true) })
+}
@@ -0,0 +1,388 @@
+package renderer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ColorKind tells how a Color is encoded
+type ColorKind uint8
+
+const (
+	ColorNone    ColorKind = iota // terminal default
+	ColorANSI                     // one of the 16 basic colors
+	ColorANSI256                  // xterm 256 color palette
+	ColorRGB                      // true color
+)
+
+// Color is the foreground or background color of a cell
+type Color struct {
+	Kind  ColorKind
+	Value uint32 // palette index, or 0xRRGGBB for ColorRGB
+}
+
+// Attr is a set of text attributes
+type Attr uint8
+
+const (
+	AttrBold Attr = 1 << iota
+	AttrFaint
+	AttrItalic
+	AttrUnderline
+	AttrBlink
+	AttrReverse
+	AttrStrikethrough
+)
+
+// CellStyle is how the content of a cell is drawn
+type CellStyle struct {
+	Fg    Color
+	Bg    Color
+	Attrs Attr
+}
+
+// Cell is one column of a row in a Rectangle
+type Cell struct {
+	// Content is the grapheme drawn in the cell. It is empty for the cells
+	// covered by a wide grapheme to their left, which have a Width of 0.
+	Content string
+	Width   int
+	Style   CellStyle
+}
+
+var blankCell = Cell{Content: " ", Width: 1}
+
+// transparent reports whether nothing visible is drawn in the cell,
+// so that what is below it shows through when overlaid
+func (c Cell) transparent() bool {
+	return c.Content == " " && c.Style.Bg.Kind == ColorNone &&
+		c.Style.Attrs&(AttrUnderline|AttrReverse|AttrStrikethrough) == 0
+}
+
+// newGrid returns height rows of width blank cells
+func newGrid(width, height int) [][]Cell {
+	grid := make([][]Cell, height)
+	cells := make([]Cell, width*height)
+	for i := range cells {
+		cells[i] = blankCell
+	}
+	for y := range grid {
+		grid[y] = cells[y*width : (y+1)*width : (y+1)*width]
+	}
+	return grid
+}
+
+// padRow returns row extended with blank cells to width,
+// or row itself if it is wide enough
+func padRow(row []Cell, width int) []Cell {
+	if len(row) >= width {
+		return row
+	}
+	padded := make([]Cell, width)
+	copy(padded, row)
+	for i := len(row); i < width; i++ {
+		padded[i] = blankCell
+	}
+	return padded
+}
+
+// drawRect draws the cells of src into dst with its top-left corner at
+// (x, y), clipping what falls outside dst. Transparent cells of src are
+// skipped. Cells of src without a background take the one below them;
+// with inherit they also take its foreground and attributes, the way a
+// container's style applies to its children.
+func drawRect(dst [][]Cell, src Rectangle, x, y int, inherit bool) {
+	for sy, srcRow := range src.Cells {
+		dy := y + sy
+		if dy < 0 || dy >= len(dst) {
+			continue
+		}
+		row := dst[dy]
+		for sx, c := range srcRow {
+			dx := x + sx
+			if dx < 0 || dx >= len(row) || c.Width == 0 || c.transparent() {
+				continue
+			}
+			under := row[dx].Style
+			if c.Style.Bg.Kind == ColorNone {
+				c.Style.Bg = under.Bg
+			}
+			if inherit {
+				if c.Style.Fg.Kind == ColorNone {
+					c.Style.Fg = under.Fg
+				}
+				c.Style.Attrs |= under.Attrs
+			}
+			setCell(row, dx, c)
+		}
+	}
+}
+
+// setCell puts c at row[x], blanking what is left of the wide graphemes
+// it overwrites. A wide grapheme that does not fit becomes a blank.
+func setCell(row []Cell, x int, c Cell) {
+	if x+c.Width > len(row) {
+		c = Cell{Content: " ", Width: 1, Style: c.Style}
+	}
+	for i := x; i < x+c.Width; i++ {
+		clearWide(row, i)
+	}
+	row[x] = c
+	for i := 1; i < c.Width; i++ {
+		row[x+i] = Cell{Style: c.Style}
+	}
+}
+
+// clearWide blanks the wide grapheme covering row[x], if any
+func clearWide(row []Cell, x int) {
+	lead := x
+	for lead > 0 && row[lead].Width == 0 {
+		lead--
+	}
+	wide := row[lead]
+	if wide.Width <= 1 || lead+wide.Width <= x {
+		return
+	}
+	for i := lead; i < lead+wide.Width && i < len(row); i++ {
+		row[i] = Cell{Content: " ", Width: 1, Style: wide.Style}
+	}
+}
+
+// parseCells splits rendered content into rows of cells, interpreting
+// SGR sequences. Other escape sequences are dropped.
+func parseCells(content string) [][]Cell {
+	var rows [][]Cell
+	var row []Cell
+	var pen CellStyle
+	var state byte
+	for len(content) > 0 {
+		seq, width, n, newState := ansi.DecodeSequence(content, state, nil)
+		state = newState
+		content = content[n:]
+
+		switch {
+		case seq == "\n":
+			rows = append(rows, row)
+			row = nil
+		case ansi.HasCsiPrefix(seq) && strings.HasSuffix(seq, "m"):
+			pen = applySGR(pen, seq)
+		case width > 0:
+			row = append(row, Cell{Content: seq, Width: width, Style: pen})
+			for i := 1; i < width; i++ {
+				row = append(row, Cell{Style: pen})
+			}
+		case len(seq) > 0 && seq[0] >= 0x20 && seq[0] != ansi.ESC && seq[0] != ansi.DEL && len(row) > 0:
+			// zero width grapheme, joins the previous one
+			row[len(row)-1].Content += seq
+		}
+	}
+	return append(rows, row)
+}
+
+// applySGR applies a Select Graphic Rendition sequence to style
+func applySGR(style CellStyle, seq string) CellStyle {
+	params := strings.FieldsFunc(seq[2:len(seq)-1], func(r rune) bool { return r == ';' || r == ':' })
+	if len(params) == 0 {
+		return CellStyle{}
+	}
+	nums := make([]int, len(params))
+	for i, p := range params {
+		nums[i], _ = strconv.Atoi(p)
+	}
+	for i := 0; i < len(nums); i++ {
+		switch n := nums[i]; {
+		case n == 0:
+			style = CellStyle{}
+		case n == 1:
+			style.Attrs |= AttrBold
+		case n == 2:
+			style.Attrs |= AttrFaint
+		case n == 3:
+			style.Attrs |= AttrItalic
+		case n == 4 || n == 21:
+			style.Attrs |= AttrUnderline
+		case n == 5 || n == 6:
+			style.Attrs |= AttrBlink
+		case n == 7:
+			style.Attrs |= AttrReverse
+		case n == 9:
+			style.Attrs |= AttrStrikethrough
+		case n == 22:
+			style.Attrs &^= AttrBold | AttrFaint
+		case n == 23:
+			style.Attrs &^= AttrItalic
+		case n == 24:
+			style.Attrs &^= AttrUnderline
+		case n == 25:
+			style.Attrs &^= AttrBlink
+		case n == 27:
+			style.Attrs &^= AttrReverse
+		case n == 29:
+			style.Attrs &^= AttrStrikethrough
+		case n >= 30 && n <= 37:
+			style.Fg = Color{Kind: ColorANSI, Value: uint32(n - 30)}
+		case n >= 90 && n <= 97:
+			style.Fg = Color{Kind: ColorANSI, Value: uint32(n - 90 + 8)}
+		case n == 39:
+			style.Fg = Color{}
+		case n >= 40 && n <= 47:
+			style.Bg = Color{Kind: ColorANSI, Value: uint32(n - 40)}
+		case n >= 100 && n <= 107:
+			style.Bg = Color{Kind: ColorANSI, Value: uint32(n - 100 + 8)}
+		case n == 49:
+			style.Bg = Color{}
+		case n == 38 || n == 48 || n == 58:
+			var c Color
+			c, i = extendedColor(nums, i)
+			if n == 38 {
+				style.Fg = c
+			} else if n == 48 {
+				style.Bg = c
+			}
+		}
+	}
+	return style
+}
+
+// extendedColor parses the 256 or true color following nums[i],
+// returning the index of its last parameter
+func extendedColor(nums []int, i int) (Color, int) {
+	if i+1 >= len(nums) {
+		return Color{}, i
+	}
+	switch nums[i+1] {
+	case 5:
+		if i+2 < len(nums) {
+			return Color{Kind: ColorANSI256, Value: uint32(nums[i+2] & 0xff)}, i + 2
+		}
+	case 2:
+		if i+4 < len(nums) {
+			r, g, b := uint32(nums[i+2]&0xff), uint32(nums[i+3]&0xff), uint32(nums[i+4]&0xff)
+			return Color{Kind: ColorRGB, Value: r<<16 | g<<8 | b}, i + 4
+		}
+	}
+	return Color{}, len(nums)
+}
+
+// sgr returns the sequence switching to style from the default style
+func (s CellStyle) sgr() string {
+	var params []string
+	attrs := []struct {
+		attr Attr
+		code string
+	}{
+		{AttrBold, "1"}, {AttrFaint, "2"}, {AttrItalic, "3"}, {AttrUnderline, "4"},
+		{AttrBlink, "5"}, {AttrReverse, "7"}, {AttrStrikethrough, "9"},
+	}
+	for _, a := range attrs {
+		if s.Attrs&a.attr != 0 {
+			params = append(params, a.code)
+		}
+	}
+	if s.Fg.Kind != ColorNone {
+		params = append(params, s.Fg.params(false))
+	}
+	if s.Bg.Kind != ColorNone {
+		params = append(params, s.Bg.params(true))
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// params returns the SGR parameters selecting c
+func (c Color) params(background bool) string {
+	base := 30
+	if background {
+		base = 40
+	}
+	switch c.Kind {
+	case ColorANSI:
+		if c.Value < 8 {
+			return strconv.Itoa(base + int(c.Value))
+		}
+		return strconv.Itoa(base + 60 + int(c.Value) - 8)
+	case ColorANSI256:
+		return strconv.Itoa(base+8) + ";5;" + strconv.Itoa(int(c.Value))
+	case ColorRGB:
+		return strconv.Itoa(base+8) + ";2;" + strconv.Itoa(int(c.Value>>16&0xff)) + ";" +
+			strconv.Itoa(int(c.Value>>8&0xff)) + ";" + strconv.Itoa(int(c.Value&0xff))
+	}
+	return ""
+}
+
+// renderRow serializes a row of cells to text with SGR sequences
+func renderRow(b *strings.Builder, row []Cell) {
+	var pen CellStyle
+	for _, c := range row {
+		if c.Width == 0 {
+			continue
+		}
+		if c.Style != pen {
+			if pen != (CellStyle{}) {
+				b.WriteString(ansi.ResetStyle)
+			}
+			if c.Style != (CellStyle{}) {
+				b.WriteString(c.Style.sgr())
+			}
+			pen = c.Style
+		}
+		b.WriteString(c.Content)
+	}
+	if pen != (CellStyle{}) {
+		b.WriteString(ansi.ResetStyle)
+	}
+}
+
+// cellStyleOf returns the colors and attributes a lipgloss style draws with
+func cellStyleOf(style lipgloss.Style) CellStyle {
+	s := CellStyle{
+		Fg: colorOf(style.GetForeground()),
+		Bg: colorOf(style.GetBackground()),
+	}
+	if style.GetBold() {
+		s.Attrs |= AttrBold
+	}
+	if style.GetFaint() {
+		s.Attrs |= AttrFaint
+	}
+	if style.GetItalic() {
+		s.Attrs |= AttrItalic
+	}
+	if style.GetUnderline() {
+		s.Attrs |= AttrUnderline
+	}
+	if style.GetBlink() {
+		s.Attrs |= AttrBlink
+	}
+	if style.GetReverse() {
+		s.Attrs |= AttrReverse
+	}
+	if style.GetStrikethrough() {
+		s.Attrs |= AttrStrikethrough
+	}
+	return s
+}
+
+// colorOf converts a lipgloss color. Colors given as a palette index keep
+// it, anything else is taken as true color.
+func colorOf(c lipgloss.TerminalColor) Color {
+	switch c := c.(type) {
+	case nil, lipgloss.NoColor:
+		return Color{}
+	case lipgloss.Color:
+		s := string(c)
+		if s == "" {
+			return Color{}
+		}
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+			if n < 16 {
+				return Color{Kind: ColorANSI, Value: uint32(n)}
+			}
+			return Color{Kind: ColorANSI256, Value: uint32(n)}
+		}
+	}
+	r, g, b, _ := c.RGBA()
+	return Color{Kind: ColorRGB, Value: (r>>8)<<16 | (g>>8)<<8 | b>>8}
+}
@@ -0,0 +1,121 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+func TestNewRectangleParsesStyles(t *testing.T) {
+	rect := NewRectangle("\x1b[1;31mA\x1b[0mB\x1b[48;5;236mC\x1b[38;2;1;2;3mD\x1b[0m")
+
+	tests := []struct {
+		x        int
+		expected CellStyle
+	}{
+		{0, CellStyle{Fg: Color{Kind: ColorANSI, Value: 1}, Attrs: AttrBold}},
+		{1, CellStyle{}},
+		{2, CellStyle{Bg: Color{Kind: ColorANSI256, Value: 236}}},
+		{3, CellStyle{Fg: Color{Kind: ColorRGB, Value: 0x010203}, Bg: Color{Kind: ColorANSI256, Value: 236}}},
+	}
+	for _, tt := range tests {
+		if got := rect.Cells[0][tt.x].Style; got != tt.expected {
+			t.Errorf("cell %d: expected style %+v, got %+v", tt.x, tt.expected, got)
+		}
+	}
+
+	// serializing and parsing again gives the same cells
+	again := NewRectangle(rect.String())
+	for x := range rect.Cells[0] {
+		if again.Cells[0][x] != rect.Cells[0][x] {
+			t.Errorf("cell %d: expected %+v after round trip, got %+v", x, rect.Cells[0][x], again.Cells[0][x])
+		}
+	}
+}
+
+func TestRectangleLines(t *testing.T) {
+	rect := NewRectangle("\x1b[1mab\x1b[0m\nc")
+	lines := rect.Lines()
+
+	if len(lines) != 2 || lines[1] != "c " {
+		t.Fatalf("expected 2 lines padded to the width, got %q", lines)
+	}
+	if joined := strings.Join(lines, "\n"); joined != rect.String() {
+		t.Errorf("expected the lines of String, got %q and %q", joined, rect.String())
+	}
+}
+
+func TestOverlayKeepsStyleBelow(t *testing.T) {
+	background := NewRectangle("\x1b[44mBBBB\x1b[0m\n\x1b[32mgreen\x1b[0m")
+	dialog := NewRectangle("X \x1b[31mY\x1b[0m")
+
+	result := Overlay(background, dialog)
+
+	if got := StripColor(result.String()); got != "XBYB \ngreen" {
+		t.Fatalf("unexpected text %q", got)
+	}
+	blue := Color{Kind: ColorANSI, Value: 4}
+	// text drawn over the background keeps it, the space lets it through
+	expected := []CellStyle{
+		{Bg: blue},
+		{Bg: blue},
+		{Fg: Color{Kind: ColorANSI, Value: 1}, Bg: blue},
+		{Bg: blue},
+	}
+	for x, style := range expected {
+		if got := result.Cells[0][x].Style; got != style {
+			t.Errorf("cell %d: expected style %+v, got %+v", x, style, got)
+		}
+	}
+	if got := result.Cells[1][0].Style.Fg; got != (Color{Kind: ColorANSI, Value: 2}) {
+		t.Errorf("expected the second row to stay green, got %+v", got)
+	}
+}
+
+func TestOverlayWideCharacters(t *testing.T) {
+	tests := []struct {
+		name     string
+		parent   string
+		child    string
+		expected string
+	}{
+		{"narrow over right half", "世界", " X", " X界"},
+		{"narrow over left half", "世界", "X", "X 界"},
+		{"wide over narrow", "abcd", " 世", "a世d"},
+		{"wide over wide boundary", "世界", " 中", " 中 "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Overlay(NewRectangle(tt.parent), NewRectangle(tt.child))
+			if got := result.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if result.Width != 4 {
+				t.Errorf("expected width 4, got %d", result.Width)
+			}
+		})
+	}
+}
+
+func TestHDivKeepsStyles(t *testing.T) {
+	hdiv := dom.HDiv(dom.DivProps{},
+		dom.Text("\x1b[31mL\x1b[0m"),
+		dom.Div(dom.DivProps{}, dom.Text("\x1b[32mR\x1b[0m"), dom.Text("r")),
+	)
+
+	cr := NewInteractiveCharmRenderer()
+	cr.SetColorProfile(termenv.ANSI)
+	result := cr.RenderToRect(hdiv, 10, 5)
+
+	if got := StripColor(result.String()); got != "LR\n r" {
+		t.Fatalf("unexpected text %q", got)
+	}
+	if got := result.Cells[0][0].Style.Fg.Value; got != 1 {
+		t.Errorf("expected left cell to stay red, got color %d", got)
+	}
+	if got := result.Cells[0][1].Style.Fg.Value; got != 2 {
+		t.Errorf("expected right cell to stay green, got color %d", got)
+	}
+}
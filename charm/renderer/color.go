@@ -0,0 +1,127 @@
+package renderer
+
+import (
+	"os"
+
+	"github.com/muesli/termenv"
+)
+
+// DetectColorProfile returns the colors the terminal on stdout supports:
+// termenv.TrueColor, ANSI256, ANSI or Ascii for none.
+// Setting NO_COLOR turns colors off, and CLICOLOR_FORCE turns them on when
+// stdout is not a terminal, like in CI logs.
+func DetectColorProfile() termenv.Profile {
+	return termenv.NewOutput(os.Stdout).EnvColorProfile()
+}
+
+// SetColorProfile sets the colors RenderToRect draws with, in place of the
+// detected ones, see DetectColorProfile. Colors the profile does not have
+// are replaced with the nearest ones it has. With termenv.Ascii, cells lose
+// their colors but keep their attributes like bold or reverse.
+func (cr *InteractiveCharmRenderer) SetColorProfile(p termenv.Profile) {
+	cr.profile = p
+	cr.degraded = nil
+}
+
+// ColorProfile returns the colors the renderer draws with
+func (cr *InteractiveCharmRenderer) ColorProfile() termenv.Profile {
+	return cr.profile
+}
+
+// degrade returns the cells of rect in the colors of the profile
+// of the renderer. The rows of rect are left untouched, since they may be
+// shared with the cache.
+func (cr *InteractiveCharmRenderer) degrade(rect Rectangle) Rectangle {
+	if cr.profile == termenv.TrueColor {
+		return rect
+	}
+	if cr.degraded == nil {
+		cr.degraded = make(map[Color]Color)
+	}
+	cells := make([][]Cell, len(rect.Cells))
+	for y, row := range rect.Cells {
+		cells[y] = make([]Cell, len(row))
+		for x, c := range row {
+			c.Style.Fg = cr.degradeColor(c.Style.Fg)
+			c.Style.Bg = cr.degradeColor(c.Style.Bg)
+			cells[y][x] = c
+		}
+	}
+	rect.Cells = cells
+	return rect
+}
+
+// degradeColor returns the nearest color to c in the profile of the renderer
+func (cr *InteractiveCharmRenderer) degradeColor(c Color) Color {
+	if c.Kind == ColorNone {
+		return c
+	}
+	if d, ok := cr.degraded[c]; ok {
+		return d
+	}
+	d := convertColor(cr.profile, c)
+	cr.degraded[c] = d
+	return d
+}
+
+// convertColor maps c to profile p: true colors to the nearest color of
+// the 256 color palette, then those to the nearest of the 16 basic colors
+func convertColor(p termenv.Profile, c Color) Color {
+	if p == termenv.Ascii {
+		return Color{}
+	}
+	if c.Kind == ColorRGB && p != termenv.TrueColor {
+		c = nearestANSI256(c.Value)
+	}
+	if c.Kind == ColorANSI256 && p == termenv.ANSI {
+		// termenv compares the colors as the eye sees them
+		c = Color{Kind: ColorANSI, Value: uint32(termenv.ANSI.Convert(termenv.ANSI256Color(c.Value)).(termenv.ANSIColor))}
+	}
+	return c
+}
+
+// cubeLevels are the levels of red, green and blue of the 6x6x6 color cube
+// of the 256 color palette, at 16 to 231
+var cubeLevels = [6]uint32{0, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+
+// nearestANSI256 returns the color of the cube or of the gray ramp, at 232
+// to 255, nearest to rgb
+func nearestANSI256(rgb uint32) Color {
+	r, g, b := rgb>>16&0xff, rgb>>8&0xff, rgb&0xff
+	level := func(v uint32) uint32 {
+		var best uint32
+		for i, l := range cubeLevels {
+			if absDiff(v, l) < absDiff(v, cubeLevels[best]) {
+				best = uint32(i)
+			}
+		}
+		return best
+	}
+	ri, gi, bi := level(r), level(g), level(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDist := distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// grays go from 0x08 to 0xee by 10
+	gray := uint32(0)
+	if avg := (r + g + b) / 3; avg > 0x08 {
+		gray = min((avg-0x08+5)/10, 23)
+	}
+	v := 0x08 + 10*gray
+	if distance(r, g, b, v, v, v) < cubeDist {
+		return Color{Kind: ColorANSI256, Value: 232 + gray}
+	}
+	return Color{Kind: ColorANSI256, Value: cube}
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// distance is the squared euclidean distance of two colors
+func distance(r1, g1, b1, r2, g2, b2 uint32) uint32 {
+	dr, dg, db := absDiff(r1, r2), absDiff(g1, g2), absDiff(b1, b2)
+	return dr*dr + dg*dg + db*db
+}
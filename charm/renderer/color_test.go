@@ -0,0 +1,104 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+// trueColorRenderer returns a renderer drawing in true color whatever the
+// terminal running the tests
+func trueColorRenderer() *InteractiveCharmRenderer {
+	cr := NewInteractiveCharmRenderer()
+	cr.SetColorProfile(termenv.TrueColor)
+	return cr
+}
+
+func TestColorProfiles(t *testing.T) {
+	root := dom.Div(dom.DivProps{},
+		dom.Text("red", styles.Style{Color: "#FF0000", BackgroundColor: "#303030", Bold: true}),
+		dom.Text("pink", styles.Style{Color: "#F25D94"}),
+	)
+	dom.NewDOM(root, &dom.Window{Width: 10, Height: 2})
+
+	tests := []struct {
+		profile termenv.Profile
+		red     CellStyle
+		pink    Color
+	}{
+		{termenv.TrueColor,
+			CellStyle{Fg: Color{Kind: ColorRGB, Value: 0xff0000}, Bg: Color{Kind: ColorRGB, Value: 0x303030}, Attrs: AttrBold},
+			Color{Kind: ColorRGB, Value: 0xf25d93}}, // lipgloss rounds the blue down
+		{termenv.ANSI256,
+			CellStyle{Fg: Color{Kind: ColorANSI256, Value: 196}, Bg: Color{Kind: ColorANSI256, Value: 236}, Attrs: AttrBold},
+			Color{Kind: ColorANSI256, Value: 204}},
+		{termenv.ANSI,
+			CellStyle{Fg: Color{Kind: ColorANSI, Value: 9}, Bg: Color{Kind: ColorANSI, Value: 0}, Attrs: AttrBold},
+			Color{Kind: ColorANSI, Value: 9}},
+		// no colors, the attributes stay
+		{termenv.Ascii, CellStyle{Attrs: AttrBold}, Color{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile.Name(), func(t *testing.T) {
+			cr := NewInteractiveCharmRenderer()
+			cr.SetColorProfile(tt.profile)
+			rect := cr.RenderToRect(root, 10, 2)
+			if got := rect.Cells[0][0].Style; got != tt.red {
+				t.Errorf("red = %+v, want %+v", got, tt.red)
+			}
+			if got := rect.Cells[1][0].Style.Fg; got != tt.pink {
+				t.Errorf("pink = %+v, want %+v", got, tt.pink)
+			}
+		})
+	}
+}
+
+func TestColorProfileKeepsCache(t *testing.T) {
+	root := dom.Text("red", styles.Style{Color: "#FF0000"})
+	dom.NewDOM(root, &dom.Window{Width: 10, Height: 1})
+
+	cr := trueColorRenderer()
+	cr.RenderToRect(root, 10, 1)
+	cr.SetColorProfile(termenv.Ascii)
+	if got := cr.RenderToRect(root, 10, 1).Cells[0][0].Style.Fg; got != (Color{}) {
+		t.Errorf("color after switching to Ascii = %+v, want none", got)
+	}
+	// degrading does not change the cached cells
+	cr.SetColorProfile(termenv.TrueColor)
+	if got, want := cr.RenderToRect(root, 10, 1).Cells[0][0].Style.Fg, (Color{Kind: ColorRGB, Value: 0xff0000}); got != want {
+		t.Errorf("color back in true color = %+v, want %+v", got, want)
+	}
+}
+
+func TestColorProfileLeavesLipglossAlone(t *testing.T) {
+	root := dom.Text("red", styles.Style{Color: "#FF0000"})
+	dom.NewDOM(root, &dom.Window{Width: 10, Height: 1})
+
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.Ascii)
+	defer lipgloss.SetColorProfile(prev)
+
+	cr := trueColorRenderer()
+	if got, want := cr.RenderToRect(root, 10, 1).Cells[0][0].Style.Fg, (Color{Kind: ColorRGB, Value: 0xff0000}); got != want {
+		t.Errorf("color with lipgloss in Ascii = %+v, want %+v", got, want)
+	}
+	if got := lipgloss.ColorProfile(); got != termenv.Ascii {
+		t.Errorf("lipgloss profile after rendering = %s, want Ascii", got.Name())
+	}
+}
+
+func TestDetectColorProfile(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("CLICOLOR_FORCE", "1")
+	if got := DetectColorProfile(); got != termenv.Ascii {
+		t.Errorf("profile with NO_COLOR = %s, want Ascii", got.Name())
+	}
+	// stdout is not a terminal under go test
+	t.Setenv("NO_COLOR", "")
+	if got := DetectColorProfile(); got != termenv.ANSI {
+		t.Errorf("profile with CLICOLOR_FORCE = %s, want ANSI", got.Name())
+	}
+}
@@ -0,0 +1,532 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// unbounded is the height the children of a scroll view are rendered into:
+// containers fit their content on an unbounded axis instead of filling it
+const unbounded = math.MaxInt32 / 2
+
+// resolveSize is dom.Size.Resolve, with percentages of an unbounded
+// size taken as auto
+func resolveSize(s dom.Size, parent int) (int, bool) {
+	if s.Unit == dom.SizePercent && parent >= unbounded {
+		return 0, false
+	}
+	return s.Resolve(parent)
+}
+
+// flexDirection is the main axis of a flex container
+type flexDirection uint8
+
+const (
+	flexColumn flexDirection = iota // Div: children top to bottom
+	flexRow                         // HDiv: children left to right
+)
+
+// fillAxes marks the axes on which a node takes exactly the size it is
+// given instead of fitting its content, because its flex container grew,
+// shrank or stretched it
+type fillAxes uint8
+
+const (
+	fillWidth fillAxes = 1 << iota
+	fillHeight
+)
+
+// split returns the main and cross components of a width and height
+func (d flexDirection) split(width, height int) (main, cross int) {
+	if d == flexRow {
+		return width, height
+	}
+	return height, width
+}
+
+// join is the inverse of split
+func (d flexDirection) join(main, cross int) (width, height int) {
+	if d == flexRow {
+		return main, cross
+	}
+	return cross, main
+}
+
+// fill returns the fillAxes for the main and cross axes
+func (d flexDirection) fill(main, cross bool) fillAxes {
+	fillW, fillH := main, cross
+	if d == flexColumn {
+		fillW, fillH = cross, main
+	}
+	var f fillAxes
+	if fillW {
+		f |= fillWidth
+	}
+	if fillH {
+		f |= fillHeight
+	}
+	return f
+}
+
+// flexItem is a child of a flex container while it is laid out
+type flexItem struct {
+	node   *dom.Node
+	fixed  bool // FixedSpacer: always exactly its own size
+	grow   int
+	shrink int
+	basis  dom.Size
+
+	minMain, maxMain   dom.Size
+	minCross, maxCross dom.Size
+
+	base int  // flex base size
+	min  int  // resolved minimum main size
+	max  int  // resolved maximum main size, -1 if none
+	size int  // final main size
+	fit  bool // rect is the item rendered to fit its content, at size
+
+	rect Rectangle
+}
+
+// flexItemOf reads the flex item props of node. Expanded components,
+// and focus scopes and keymaps around a single node, are sized by the node inside.
+func flexItemOf(node *dom.Node, dir flexDirection) flexItem {
+	item := flexItem{node: node, shrink: 1}
+	for (node.Type == dom.ElementTypeComponent && node.State != nil && len(node.Children) > 0) ||
+		((node.Type == dom.ElementTypeFocusScope || node.Type == dom.ElementTypeKeyMap) && len(node.Children) == 1 && node.Children[0] != nil) {
+		node = node.Children[0]
+	}
+	switch node.Type {
+	case dom.ElementTypeFixedSpacer:
+		item.fixed = true
+		return item
+	case dom.ElementTypeSpacer:
+		if dir == flexRow {
+			// spacers take the free space left to right
+			item.grow = 1
+			item.shrink = 0
+		}
+		return item
+	case dom.ElementTypeScrollView:
+		if dir == flexColumn && dom.ExtractProps[dom.ScrollViewProps](node.Props).Height == 0 {
+			// scroll views take the height their siblings leave
+			item.grow = 1
+			item.basis = dom.Cells(0)
+		}
+		return item
+	}
+	p, ok := node.Props.(dom.StructProps[dom.DivProps])
+	if !ok {
+		return item
+	}
+	props := p.Value
+	item.grow = props.FlexGrow
+	if props.FlexShrink != nil {
+		item.shrink = *props.FlexShrink
+	}
+	item.basis = props.FlexBasis
+	if dir == flexRow {
+		item.minMain, item.maxMain = props.MinWidth, props.MaxWidth
+		item.minCross, item.maxCross = props.MinHeight, props.MaxHeight
+	} else {
+		item.minMain, item.maxMain = props.MinHeight, props.MaxHeight
+		item.minCross, item.maxCross = props.MinWidth, props.MaxWidth
+	}
+	return item
+}
+
+// flexContainer holds the container props of a Div or HDiv
+type flexContainer struct {
+	dir     flexDirection
+	justify dom.Justify
+	align   dom.Align
+	gap     int
+}
+
+func flexContainerOf(vnode *dom.Node, dir flexDirection) flexContainer {
+	c := flexContainer{dir: dir}
+	p, ok := vnode.Props.(dom.StructProps[dom.DivProps])
+	if !ok {
+		return c
+	}
+	c.justify = p.Value.JustifyContent
+	c.align = p.Value.AlignItems
+	if c.align == "" {
+		c.align = p.Value.Align
+	}
+	c.gap = p.Value.Gap
+	return c
+}
+
+// renderFlexToRect lays out the children of vnode along dir inside width
+// and height, in two passes: children are first measured at the size of
+// their content, then the free space is distributed, or the missing space
+// taken back, and the children whose size changed are rendered again at
+// their final size.
+func (cr *InteractiveCharmRenderer) renderFlexToRect(vnode *dom.Node, dir flexDirection, width, height int, fill fillAxes) Rectangle {
+	c := flexContainerOf(vnode, dir)
+	mainAvail, crossAvail := dir.split(max(width, 0), max(height, 0))
+	fillMain, fillCross := dir.split(int(fill&fillWidth), int(fill&fillHeight))
+	bounded := mainAvail < unbounded
+	if crossAvail >= unbounded {
+		fillCross = 0
+	}
+
+	// measure pass: each child gets the space the previous ones left
+	items := make([]flexItem, 0, len(vnode.Children))
+	grows := false
+	used := 0
+	for _, child := range vnode.Children {
+		if child == nil {
+			continue
+		}
+		item := flexItemOf(child, dir)
+		if !bounded {
+			// there is no free space to grow into
+			item.grow = 0
+			item.basis = dom.Size{}
+		}
+		remaining := mainAvail - used
+		switch {
+		case item.fixed:
+			if dir == flexRow {
+				item.rect = cr.renderFixedSpacerForHorizontal(child)
+			} else {
+				item.rect = cr.renderFixedSpacerForVertical(child)
+			}
+			item.base, _ = dir.split(item.rect.Width, item.rect.Height)
+			item.fit = true
+		case item.basis.Unit != dom.SizeAuto:
+			item.base, _ = resolveSize(item.basis, mainAvail)
+		case remaining <= 0 && item.grow == 0:
+			// no room left, like a child that renders nothing
+			continue
+		default:
+			w, h := dir.join(max(remaining, 0), crossAvail)
+			item.rect = cr.renderNodeToRect(child, w, h)
+			item.base, _ = dir.split(item.rect.Width, item.rect.Height)
+			item.fit = true
+			if item.base == 0 && item.grow == 0 {
+				continue
+			}
+		}
+
+		item.min = 0
+		if v, ok := resolveSize(item.minMain, mainAvail); ok {
+			item.min = v
+		} else if item.fit && !item.fixed {
+			// content sized items do not shrink below their content
+			item.min = item.base
+		}
+		item.max = -1
+		if v, ok := resolveSize(item.maxMain, mainAvail); ok {
+			item.max = max(v, item.min)
+		}
+		if item.fixed {
+			item.min, item.max = item.base, item.base
+		}
+		item.size = clampSize(item.base, item.min, item.max)
+		used += item.size
+		if len(items) > 0 {
+			used += c.gap
+		}
+		if item.grow > 0 {
+			grows = true
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return Rectangle{}
+	}
+
+	gaps := c.gap * (len(items) - 1)
+	mainSize := min(used, mainAvail)
+	if bounded && (fillMain != 0 || grows || (c.justify != "" && c.justify != dom.JustifyStart)) {
+		mainSize = mainAvail
+	}
+	resolveFlexSizes(items, mainSize-gaps)
+
+	// arrange pass: render again the children whose size changed
+	crossSize := 0
+	for i := range items {
+		item := &items[i]
+		if item.fixed {
+			continue
+		}
+		itemMain, _ := dir.split(item.rect.Width, item.rect.Height)
+		if !item.fit || itemMain != item.size {
+			w, h := dir.join(item.size, crossAvail)
+			item.rect = cr.renderNodeToSize(item.node, w, h, dir.fill(true, false))
+		}
+		_, itemCross := dir.split(item.rect.Width, item.rect.Height)
+		itemCross = clampCross(item, itemCross, crossAvail)
+		crossSize = max(crossSize, itemCross)
+	}
+	if fillCross != 0 {
+		crossSize = crossAvail
+	}
+	for i := range items {
+		item := &items[i]
+		if item.fixed {
+			continue
+		}
+		_, itemCross := dir.split(item.rect.Width, item.rect.Height)
+		target := itemCross
+		if c.align == dom.AlignStretch {
+			target = crossSize
+		}
+		target = clampCross(item, target, crossAvail)
+		if target != itemCross {
+			w, h := dir.join(item.size, target)
+			item.rect = cr.renderNodeToSize(item.node, w, h, dir.fill(true, true))
+		}
+	}
+
+	return composeFlex(items, c, mainSize, crossSize)
+}
+
+// clampCross applies the min and max cross size of item to size
+func clampCross(item *flexItem, size, crossAvail int) int {
+	lo, _ := resolveSize(item.minCross, crossAvail)
+	hi, ok := resolveSize(item.maxCross, crossAvail)
+	if !ok {
+		hi = -1
+	}
+	return clampSize(size, lo, hi)
+}
+
+func clampSize(size, lo, hi int) int {
+	if hi >= 0 && size > hi {
+		size = hi
+	}
+	if size < lo {
+		size = lo
+	}
+	return size
+}
+
+// resolveFlexSizes sets the final main size of items so that they fill
+// space: growing them by their grow factor when there is room left,
+// shrinking them by their shrink factor scaled by their base size when
+// there is not. Items that would break their min or max size are frozen
+// at it and the rest distributed again.
+func resolveFlexSizes(items []flexItem, space int) {
+	sum := 0
+	for _, item := range items {
+		sum += item.size
+	}
+	growing := space > sum
+
+	targets := make([]float64, len(items))
+	frozen := make([]bool, len(items))
+	for i, item := range items {
+		targets[i] = float64(item.base)
+		factor := item.grow
+		if !growing {
+			factor = item.shrink * item.base
+		}
+		if factor == 0 || item.fixed || space == sum {
+			frozen[i] = true
+			targets[i] = float64(item.size)
+		}
+	}
+
+	for {
+		free := float64(space)
+		var factors float64
+		for i, item := range items {
+			if frozen[i] {
+				free -= targets[i]
+				continue
+			}
+			free -= float64(item.base)
+			if growing {
+				factors += float64(item.grow)
+			} else {
+				factors += float64(item.shrink * item.base)
+			}
+		}
+		if factors == 0 {
+			break
+		}
+
+		var violation float64
+		for i, item := range items {
+			if frozen[i] {
+				continue
+			}
+			factor := float64(item.grow)
+			if !growing {
+				factor = float64(item.shrink * item.base)
+			}
+			target := float64(item.base) + free*factor/factors
+			clamped := math.Max(target, float64(item.min))
+			if item.max >= 0 {
+				clamped = math.Min(clamped, float64(item.max))
+			}
+			targets[i] = clamped
+			violation += clamped - target
+		}
+		if violation == 0 {
+			break
+		}
+		// freeze the items clamped in the direction of the total violation
+		froze := false
+		for i, item := range items {
+			if frozen[i] {
+				continue
+			}
+			if (violation > 0 && targets[i] == float64(item.min)) ||
+				(violation < 0 && item.max >= 0 && targets[i] == float64(item.max)) {
+				frozen[i] = true
+				froze = true
+			}
+		}
+		if !froze {
+			break
+		}
+	}
+
+	// round so that the sizes add up exactly
+	var acc float64
+	prev := 0
+	for i := range items {
+		acc += targets[i]
+		next := int(math.Round(acc))
+		items[i].size = next - prev
+		prev = next
+	}
+}
+
+// composeFlex places the items one after the other along the main axis,
+// distributing the space they leave according to justify, and aligns
+// them on the cross axis. Items are clipped to their slot.
+func composeFlex(items []flexItem, c flexContainer, mainSize, crossSize int) Rectangle {
+	n := len(items)
+	total := c.gap * (n - 1)
+	for _, item := range items {
+		total += item.size
+	}
+	free := max(mainSize-total, 0)
+	mainSize = max(mainSize, total)
+
+	// the free space before item i is free*num(i)/den
+	num, den := func(i int) int { return 0 }, 1
+	switch c.justify {
+	case dom.JustifyEnd:
+		num = func(i int) int { return 1 }
+	case dom.JustifyCenter:
+		num, den = func(i int) int { return 1 }, 2
+	case dom.JustifySpaceBetween:
+		if n > 1 {
+			num, den = func(i int) int { return i }, n-1
+		}
+	case dom.JustifySpaceAround:
+		num, den = func(i int) int { return 2*i + 1 }, 2*n
+	case dom.JustifySpaceEvenly:
+		num, den = func(i int) int { return i + 1 }, n+1
+	}
+
+	width, height := c.dir.join(mainSize, crossSize)
+	var cells [][]Cell
+	var boxes []*layoutBox
+	if c.dir == flexRow {
+		cells = newGrid(width, height)
+	} else {
+		cells = make([][]Cell, height)
+	}
+
+	pos := 0
+	for i, item := range items {
+		w, h := c.dir.join(item.size, crossSize)
+		rect := clipRect(item.rect, w, h)
+		_, itemCross := c.dir.split(rect.Width, rect.Height)
+		offset := 0
+		switch c.align {
+		case dom.AlignCenter:
+			offset = (crossSize - itemCross) / 2
+		case dom.AlignEnd, dom.AlignBottom, dom.AlignRight:
+			offset = crossSize - itemCross
+		}
+		x, y := c.dir.join(pos+(2*free*num(i)+den)/(2*den), offset)
+
+		if c.dir == flexRow {
+			drawRect(cells, rect, x, y, false)
+		} else {
+			placeRows(cells[y:y+item.size], rect, x, width)
+		}
+		boxes = append(boxes, translateBoxes(rect.boxes, x, y)...)
+
+		pos += item.size + c.gap
+	}
+	if c.dir == flexColumn {
+		blank := newGrid(width, 1)[0]
+		for y, row := range cells {
+			if row == nil {
+				cells[y] = blank
+			}
+		}
+	}
+
+	return Rectangle{
+		Width:  width,
+		Height: height,
+		Cells:  cells,
+		boxes:  boxes,
+	}
+}
+
+// placeRows fills dst with the rows of rect shifted right by x, as rows of
+// width cells. Rows that need no shifting are shared with rect.
+func placeRows(dst [][]Cell, rect Rectangle, x, width int) {
+	for y := range dst {
+		if y >= len(rect.Cells) {
+			break
+		}
+		row := rect.Cells[y]
+		if x == 0 {
+			dst[y] = padRow(row, width)
+			continue
+		}
+		shifted := newGrid(width, 1)
+		drawRect(shifted, Rectangle{Width: len(row), Height: 1, Cells: [][]Cell{row}}, x, 0, false)
+		dst[y] = shifted[0]
+	}
+}
+
+// fitRect pads or clips rect to exactly width and height on the filled axes
+func fitRect(rect Rectangle, width, height int, fill fillAxes) Rectangle {
+	w, h := rect.Width, rect.Height
+	if fill&fillWidth != 0 {
+		w = max(width, 0)
+	}
+	if fill&fillHeight != 0 {
+		h = max(height, 0)
+	}
+	if w == rect.Width && h == rect.Height {
+		return rect
+	}
+	rect = clipRect(rect, w, h)
+	if rect.Width == w && rect.Height == h {
+		return rect
+	}
+	cells := make([][]Cell, h)
+	var blank []Cell
+	for y := range cells {
+		if y < len(rect.Cells) {
+			cells[y] = padRow(rect.Cells[y], w)
+			continue
+		}
+		if blank == nil {
+			blank = newGrid(w, 1)[0]
+		}
+		cells[y] = blank
+	}
+	return Rectangle{
+		Width:  w,
+		Height: h,
+		Cells:  cells,
+		boxes:  rect.boxes,
+	}
+}
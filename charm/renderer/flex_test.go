@@ -0,0 +1,223 @@
+package renderer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+func TestFlexRow(t *testing.T) {
+	a := func(props dom.DivProps) *dom.Node { return dom.Div(props, dom.Text("A")) }
+	b := func(props dom.DivProps) *dom.Node { return dom.Div(props, dom.Text("B")) }
+
+	tests := []struct {
+		name     string
+		hdiv     *dom.Node
+		expected string
+	}{
+		{
+			name:     "FitsContent",
+			hdiv:     dom.HDiv(dom.DivProps{}, a(dom.DivProps{}), b(dom.DivProps{})),
+			expected: "AB",
+		},
+		{
+			name:     "SpacerTakesFreeSpace",
+			hdiv:     dom.HDiv(dom.DivProps{}, a(dom.DivProps{}), dom.Spacer(), b(dom.DivProps{})),
+			expected: "A        B",
+		},
+		{
+			name:     "Gap",
+			hdiv:     dom.HDiv(dom.DivProps{Gap: 2}, a(dom.DivProps{}), b(dom.DivProps{})),
+			expected: "A  B",
+		},
+		{
+			name:     "JustifyEnd",
+			hdiv:     dom.HDiv(dom.DivProps{JustifyContent: dom.JustifyEnd}, a(dom.DivProps{}), b(dom.DivProps{})),
+			expected: "        AB",
+		},
+		{
+			name:     "JustifyCenter",
+			hdiv:     dom.HDiv(dom.DivProps{JustifyContent: dom.JustifyCenter}, a(dom.DivProps{}), b(dom.DivProps{})),
+			expected: "    AB    ",
+		},
+		{
+			name:     "JustifySpaceBetween",
+			hdiv:     dom.HDiv(dom.DivProps{JustifyContent: dom.JustifySpaceBetween}, a(dom.DivProps{}), b(dom.DivProps{}), a(dom.DivProps{})),
+			expected: "A    B   A",
+		},
+		{
+			name:     "JustifySpaceEvenly",
+			hdiv:     dom.HDiv(dom.DivProps{JustifyContent: dom.JustifySpaceEvenly}, a(dom.DivProps{}), b(dom.DivProps{})),
+			expected: "   A  B   ",
+		},
+		{
+			name:     "GrowMovesLaterChildren",
+			hdiv:     dom.HDiv(dom.DivProps{}, a(dom.DivProps{FlexGrow: 1}), b(dom.DivProps{})),
+			expected: "A        B",
+		},
+		{
+			name:     "BasisPercent",
+			hdiv:     dom.HDiv(dom.DivProps{}, a(dom.DivProps{FlexBasis: dom.Percent(30)}), b(dom.DivProps{})),
+			expected: "A  B",
+		},
+		{
+			name: "ShrinkToFit",
+			hdiv: dom.HDiv(dom.DivProps{},
+				a(dom.DivProps{FlexBasis: dom.Cells(8)}),
+				b(dom.DivProps{FlexBasis: dom.Cells(8)}),
+			),
+			expected: "A    B    ",
+		},
+		{
+			name: "NoShrink",
+			hdiv: dom.HDiv(dom.DivProps{},
+				a(dom.DivProps{FlexBasis: dom.Cells(8), FlexShrink: styles.Int(0)}),
+				b(dom.DivProps{FlexBasis: dom.Cells(8)}),
+			),
+			expected: "A       B ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rect := NewInteractiveCharmRenderer().RenderToRect(tt.hdiv, 10, 5)
+			if got := StripColor(rect.String()); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFlexGrowSizes(t *testing.T) {
+	left := dom.Div(dom.DivProps{FlexGrow: 1}, dom.Text("left"))
+	middle := dom.Div(dom.DivProps{FlexGrow: 2, MaxWidth: dom.Cells(6)}, dom.Text("mid"))
+	right := dom.Div(dom.DivProps{FlexGrow: 2}, dom.Text("right"))
+	hdiv := dom.HDiv(dom.DivProps{}, left, middle, right)
+
+	rect := NewInteractiveCharmRenderer().RenderToRect(hdiv, 30, 5)
+
+	// 18 free cells: middle reaches its max of 6 after 3, the 15 left
+	// are shared 1:2 between left and right
+	var widths []int
+	for _, node := range []*dom.Node{left, middle, right} {
+		bounds, ok := rect.Layout.BoundsOf(node)
+		if !ok {
+			t.Fatalf("expected %v in layout", node)
+		}
+		widths = append(widths, bounds.Width)
+	}
+	if expected := []int{9, 6, 15}; !reflect.DeepEqual(widths, expected) {
+		t.Errorf("expected widths %v, got %v", expected, widths)
+	}
+}
+
+func TestFlexColumn(t *testing.T) {
+	header := dom.Text("header")
+	body := dom.Div(dom.DivProps{FlexGrow: 1}, dom.Text("body"))
+	footer := dom.Text("footer")
+	div := dom.Div(dom.DivProps{}, header, body, footer)
+
+	rect := NewInteractiveCharmRenderer().RenderToRect(div, 8, 5)
+
+	expected := "header\nbody  \n      \n      \nfooter"
+	if got := StripColor(rect.String()); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	bounds, _ := rect.Layout.BoundsOf(body)
+	if bounds.Height != 3 {
+		t.Errorf("expected body to grow to 3 lines, got %+v", bounds)
+	}
+}
+
+func TestFlexAlignItems(t *testing.T) {
+	tall := func() *dom.Node { return dom.Div(dom.DivProps{}, dom.Text("1"), dom.Text("2"), dom.Text("3")) }
+
+	tests := []struct {
+		align    dom.Align
+		expected string
+	}{
+		{"", "1x\n2 \n3 "},
+		{dom.AlignCenter, "1 \n2x\n3 "},
+		{dom.AlignEnd, "1 \n2 \n3x"},
+		{dom.AlignBottom, "1 \n2 \n3x"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.align), func(t *testing.T) {
+			hdiv := dom.HDiv(dom.DivProps{AlignItems: tt.align}, tall(), dom.Text("x"))
+			rect := NewInteractiveCharmRenderer().RenderToRect(hdiv, 10, 5)
+			if got := StripColor(rect.String()); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("stretch", func(t *testing.T) {
+		short := dom.Div(dom.DivProps{}, dom.Text("x"))
+		hdiv := dom.HDiv(dom.DivProps{AlignItems: dom.AlignStretch}, tall(), short)
+		rect := NewInteractiveCharmRenderer().RenderToRect(hdiv, 10, 5)
+		bounds, _ := rect.Layout.BoundsOf(short)
+		if bounds.Height != 3 {
+			t.Errorf("expected short child stretched to 3 lines, got %+v", bounds)
+		}
+	})
+}
+
+// TestFlexSplitPane tests bordered panes that take a share of the window
+func TestFlexSplitPane(t *testing.T) {
+	pane := func(props dom.DivProps, text string) *dom.Node {
+		props.Style = styles.Style{BorderRouned: true}
+		return dom.Div(props, dom.Text(text))
+	}
+	root := dom.HDiv(dom.DivProps{Height: 4, AlignItems: dom.AlignStretch},
+		pane(dom.DivProps{FlexBasis: dom.Percent(40)}, "nav"),
+		pane(dom.DivProps{FlexGrow: 1}, "main"),
+	)
+
+	for _, width := range []int{20, 30} {
+		rect := NewInteractiveCharmRenderer().RenderToRect(root, width, 4)
+		if rect.Width != width || rect.Height != 4 {
+			t.Fatalf("expected %dx4, got %dx%d:\n%s", width, rect.Width, rect.Height, StripColor(rect.String()))
+		}
+		nav, _ := rect.Layout.BoundsOf(root.Children[0])
+		main, _ := rect.Layout.BoundsOf(root.Children[1])
+		if nav.Width != width*40/100 || nav.X+nav.Width != main.X || main.X+main.Width != width {
+			t.Errorf("width %d: unexpected panes %+v %+v", width, nav, main)
+		}
+		if nav.Height != 4 || main.Height != 4 {
+			t.Errorf("width %d: expected panes stretched to 4 lines, got %+v %+v", width, nav, main)
+		}
+	}
+}
+
+func TestResolveFlexSizes(t *testing.T) {
+	item := func(base, grow, shrink, min, max int) flexItem {
+		return flexItem{base: base, size: clampSize(base, min, max), grow: grow, shrink: shrink, min: min, max: max}
+	}
+	tests := []struct {
+		name     string
+		items    []flexItem
+		space    int
+		expected []int
+	}{
+		{"no free space", []flexItem{item(3, 1, 1, 0, -1), item(4, 1, 1, 0, -1)}, 7, []int{3, 4}},
+		{"grow evenly", []flexItem{item(0, 1, 1, 0, -1), item(0, 1, 1, 0, -1)}, 10, []int{5, 5}},
+		{"grow rounds to space", []flexItem{item(0, 1, 1, 0, -1), item(0, 1, 1, 0, -1), item(0, 1, 1, 0, -1)}, 10, []int{3, 4, 3}},
+		{"grow respects max", []flexItem{item(0, 1, 1, 0, 2), item(0, 1, 1, 0, -1)}, 10, []int{2, 8}},
+		{"shrink by base", []flexItem{item(10, 0, 1, 0, -1), item(30, 0, 1, 0, -1)}, 20, []int{5, 15}},
+		{"shrink respects min", []flexItem{item(10, 0, 1, 8, -1), item(30, 0, 1, 0, -1)}, 20, []int{8, 12}},
+		{"overflow when nothing shrinks", []flexItem{item(10, 0, 0, 0, -1), item(10, 0, 1, 10, -1)}, 15, []int{10, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolveFlexSizes(tt.items, tt.space)
+			var got []int
+			for _, item := range tt.items {
+				got = append(got, item.size)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package renderer
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// Layout maps every rendered node to where it ended up on screen.
+// It is produced by RenderToRect alongside the Rectangle.
+type Layout struct {
+	Root *LayoutNode
+
+	index map[*dom.Node]*LayoutNode
+}
+
+// LayoutNode is the box of one rendered node
+type LayoutNode struct {
+	Node    *dom.Node
+	Bounds  dom.Rect   // Border box in absolute screen cells, margins excluded
+	Border  dom.Insets // Border widths inside Bounds
+	Padding dom.Insets // Padding inside the border
+
+	// Scroll is set on scroll views, its Viewport is the content bounds
+	Scroll *dom.ScrollExtent
+
+	Parent   *LayoutNode
+	Children []*LayoutNode // In paint order: later children are drawn on top
+}
+
+// ContentBounds returns the area inside the border and padding
+func (n *LayoutNode) ContentBounds() dom.Rect {
+	return n.Bounds.Inset(n.Border).Inset(n.Padding)
+}
+
+// NodeAt returns the deepest node rendered at cell (x, y), or nil.
+// Where boxes overlap, the one painted last wins.
+// NodeAt makes Layout a dom.HitTester.
+func (l *Layout) NodeAt(x, y int) *dom.Node {
+	if l == nil || l.Root == nil {
+		return nil
+	}
+	if n := l.Root.find(x, y); n != nil {
+		return n.Node
+	}
+	return nil
+}
+
+// BoundsOf returns the border box of node, and false if node was not rendered
+func (l *Layout) BoundsOf(node *dom.Node) (dom.Rect, bool) {
+	n := l.Lookup(node)
+	if n == nil {
+		return dom.Rect{}, false
+	}
+	return n.Bounds, true
+}
+
+// ScrollExtentOf returns the geometry of a rendered scroll view.
+// With BoundsOf it makes Layout a dom.ScrollMeasurer.
+func (l *Layout) ScrollExtentOf(node *dom.Node) (dom.ScrollExtent, bool) {
+	n := l.Lookup(node)
+	if n == nil || n.Scroll == nil {
+		return dom.ScrollExtent{}, false
+	}
+	return *n.Scroll, true
+}
+
+// Lookup returns the layout of node, or nil if node was not rendered
+func (l *Layout) Lookup(node *dom.Node) *LayoutNode {
+	if l == nil {
+		return nil
+	}
+	return l.index[node]
+}
+
+func (n *LayoutNode) find(x, y int) *LayoutNode {
+	if n.Scroll != nil && !n.Scroll.Viewport.Contains(x, y) {
+		// the children of a scroll view are cut to its viewport
+		if n.Bounds.Contains(x, y) {
+			return n
+		}
+		return nil
+	}
+	// children may overflow their parent, so they are searched regardless
+	for i := len(n.Children) - 1; i >= 0; i-- {
+		if found := n.Children[i].find(x, y); found != nil {
+			return found
+		}
+	}
+	if n.Bounds.Contains(x, y) {
+		return n
+	}
+	return nil
+}
+
+// layoutBox is the box of a node while rendering. Top-level boxes of a
+// Rectangle are positioned relative to the Rectangle, nested boxes relative
+// to their parent box, so moving a Rectangle only touches its top-level boxes.
+type layoutBox struct {
+	node    *dom.Node
+	x       int
+	y       int
+	width   int
+	height  int
+	border  dom.Insets
+	padding dom.Insets
+	scroll  *scrollBox
+
+	children []*layoutBox
+}
+
+// scrollBox is what a scroll view records about its content
+type scrollBox struct {
+	offset        int
+	contentHeight int
+}
+
+// withBox records vnode as the box wrapping the whole rectangle,
+// margins excluded, with its current boxes as children
+func withBox(rect Rectangle, vnode *dom.Node, margin, border, padding dom.Insets) Rectangle {
+	box := &layoutBox{
+		node:     vnode,
+		x:        margin.Left,
+		y:        margin.Top,
+		width:    rect.Width - margin.Left - margin.Right,
+		height:   rect.Height - margin.Top - margin.Bottom,
+		border:   border,
+		padding:  padding,
+		scroll:   rect.scroll,
+		children: translateBoxes(rect.boxes, -margin.Left, -margin.Top),
+	}
+	if box.width < 0 {
+		box.width = 0
+	}
+	if box.height < 0 {
+		box.height = 0
+	}
+	rect.boxes = []*layoutBox{box}
+	rect.scroll = nil
+	return rect
+}
+
+// translateBoxes returns copies of the top-level boxes moved by (dx, dy)
+func translateBoxes(boxes []*layoutBox, dx, dy int) []*layoutBox {
+	if len(boxes) == 0 {
+		return nil
+	}
+	moved := make([]*layoutBox, len(boxes))
+	for i, box := range boxes {
+		cp := *box
+		cp.x += dx
+		cp.y += dy
+		moved[i] = &cp
+	}
+	return moved
+}
+
+// newLayout resolves the boxes of a final Rectangle to absolute positions
+func newLayout(boxes []*layoutBox) *Layout {
+	l := &Layout{index: make(map[*dom.Node]*LayoutNode)}
+	roots := l.resolve(boxes, nil, 0, 0)
+	switch len(roots) {
+	case 0:
+	case 1:
+		l.Root = roots[0]
+	default:
+		l.Root = &LayoutNode{Children: roots}
+		for _, root := range roots {
+			root.Parent = l.Root
+		}
+	}
+	return l
+}
+
+func (l *Layout) resolve(boxes []*layoutBox, parent *LayoutNode, originX, originY int) []*LayoutNode {
+	if len(boxes) == 0 {
+		return nil
+	}
+	nodes := make([]*LayoutNode, 0, len(boxes))
+	for _, box := range boxes {
+		n := &LayoutNode{
+			Node: box.node,
+			Bounds: dom.Rect{
+				X:      originX + box.x,
+				Y:      originY + box.y,
+				Width:  box.width,
+				Height: box.height,
+			},
+			Border:  box.border,
+			Padding: box.padding,
+			Parent:  parent,
+		}
+		if box.scroll != nil {
+			n.Scroll = &dom.ScrollExtent{
+				Viewport:      n.ContentBounds(),
+				Offset:        box.scroll.offset,
+				ContentHeight: box.scroll.contentHeight,
+			}
+		}
+		n.Children = l.resolve(box.children, n, n.Bounds.X, n.Bounds.Y)
+		if box.node != nil {
+			l.index[box.node] = n
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// styleInsets extracts the margin, border and padding a lipgloss style adds
+// around its content. Inline styles render none of them.
+func styleInsets(style lipgloss.Style) (margin, border, padding dom.Insets) {
+	if style.GetInline() {
+		return
+	}
+	margin = dom.Insets{
+		Top:    style.GetMarginTop(),
+		Right:  style.GetMarginRight(),
+		Bottom: style.GetMarginBottom(),
+		Left:   style.GetMarginLeft(),
+	}
+	border = dom.Insets{
+		Top:    style.GetBorderTopSize(),
+		Right:  style.GetBorderRightSize(),
+		Bottom: style.GetBorderBottomSize(),
+		Left:   style.GetBorderLeftSize(),
+	}
+	padding = dom.Insets{
+		Top:    style.GetPaddingTop(),
+		Right:  style.GetPaddingRight(),
+		Bottom: style.GetPaddingBottom(),
+		Left:   style.GetPaddingLeft(),
+	}
+	return
+}
@@ -0,0 +1,83 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+func TestLayoutNodeAt(t *testing.T) {
+	left := dom.Text("Left")
+	right := dom.Text("Right")
+	below := dom.Text("Below")
+	root := dom.Div(dom.DivProps{},
+		dom.HDiv(dom.DivProps{}, left, dom.FixedSpacer(2), right),
+		below,
+	)
+
+	layout := NewInteractiveCharmRenderer().RenderToRect(root, 40, 10).Layout
+
+	tests := []struct {
+		name string
+		x, y int
+		want *dom.Node
+	}{
+		{"first char of left", 0, 0, left},
+		{"last char of left", 3, 0, left},
+		{"spacer falls back to hdiv", 4, 0, root.Children[0]},
+		{"first char of right", 6, 0, right},
+		{"second line", 2, 1, below},
+		{"outside content", 30, 5, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := layout.NodeAt(tt.x, tt.y)
+			if got != tt.want {
+				t.Errorf("NodeAt(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutBoundsWithBorderAndPadding(t *testing.T) {
+	text := dom.Text("Hi")
+	box := dom.Div(dom.DivProps{Style: styles.Style{
+		BorderRouned: true,
+		PaddingLeft:  styles.Int(2),
+		PaddingTop:   styles.Int(1),
+	}}, text)
+	root := dom.Div(dom.DivProps{}, dom.Text("Header"), box)
+
+	layout := NewInteractiveCharmRenderer().RenderToRect(root, 40, 10).Layout
+
+	bounds, ok := layout.BoundsOf(box)
+	if !ok {
+		t.Fatalf("expected box to be laid out")
+	}
+	// 1 border + 2 padding + "Hi" + 1 border wide, 1 border + 1 padding + 1 line + 1 border high
+	expected := dom.Rect{X: 0, Y: 1, Width: 6, Height: 4}
+	if bounds != expected {
+		t.Errorf("expected bounds %+v, got %+v", expected, bounds)
+	}
+
+	node := layout.Lookup(box)
+	if node.Border != (dom.Insets{Top: 1, Right: 1, Bottom: 1, Left: 1}) {
+		t.Errorf("unexpected border insets %+v", node.Border)
+	}
+	content := node.ContentBounds()
+	if content != (dom.Rect{X: 3, Y: 3, Width: 2, Height: 1}) {
+		t.Errorf("unexpected content bounds %+v", content)
+	}
+
+	textBounds, _ := layout.BoundsOf(text)
+	if textBounds != (dom.Rect{X: 3, Y: 3, Width: 2, Height: 1}) {
+		t.Errorf("unexpected text bounds %+v", textBounds)
+	}
+	if layout.NodeAt(3, 3) != text {
+		t.Errorf("expected text at (3, 3)")
+	}
+	if layout.NodeAt(1, 2) != box {
+		t.Errorf("expected padding to belong to the box")
+	}
+}
@@ -2,15 +2,18 @@ package renderer
 
 import (
 	"fmt"
+	"io"
 	"regexp"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/xhd2015/go-dom-tui/dom"
 	"github.com/xhd2015/go-dom-tui/log"
 	"github.com/xhd2015/go-dom-tui/react"
 	"github.com/xhd2015/go-dom-tui/styles"
+	"github.com/xhd2015/go-dom-tui/theme"
 )
 
 // stripANSI removes ANSI escape sequences from a string to get visual width
@@ -25,15 +28,35 @@ type InteractiveCharmRenderer struct {
 	styles       CharmStyles
 	lastWasBlock bool // tracks if the last rendered element was a block element
 	needsNewline bool // tracks if we need a newline before the next block element
+
+	cache *renderCache // rectangles of the last RenderToRect, see renderCache
+
+	// lipgloss draws the styles of this renderer in true color, for
+	// RenderToRect to degrade them to profile
+	lipgloss *lipgloss.Renderer
+
+	profile  termenv.Profile // colors RenderToRect draws with, see SetColorProfile
+	degraded map[Color]Color // colors converted to profile so far
 }
 
 // NewInteractiveCharmRenderer creates a new interactive renderer with styled components
 func NewInteractiveCharmRenderer() *InteractiveCharmRenderer {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.TrueColor)
 	return &InteractiveCharmRenderer{
-		styles: defaultStyles(),
+		styles:   newCharmStyles(r, theme.Dark()),
+		lipgloss: r,
+		profile:  DetectColorProfile(),
 	}
 }
 
+// SetTheme renders the following frames with the styles of t
+func (cr *InteractiveCharmRenderer) SetTheme(t theme.Theme) {
+	cr.styles = newCharmStyles(cr.lipgloss, t)
+	// cached rectangles hold the styles of the previous theme
+	cr.cache = nil
+}
+
 func RenderToString(vnode *dom.Node) string {
 	cr := NewInteractiveCharmRenderer()
 	return cr.Render(vnode)
@@ -66,7 +89,7 @@ func (cr *InteractiveCharmRenderer) shouldAddNewlineBeforeBlock() bool {
 // isBlockElementType checks if an element type is a block element
 func (cr *InteractiveCharmRenderer) isBlockElementType(elementType string) bool {
 	return elementType == dom.ElementTypeDiv || elementType == dom.ElementTypeHDiv ||
-		elementType == dom.ElementTypeZDiv ||
+		elementType == dom.ElementTypeZDiv || elementType == dom.ElementTypeScrollView ||
 		elementType == dom.ElementTypeP || elementType == dom.ElementTypeH1 ||
 		elementType == dom.ElementTypeH2
 }
@@ -83,6 +106,10 @@ func (cr *InteractiveCharmRenderer) Render(vnode *dom.Node) string {
 		cr.updateStylesForWindowSize(width, height)
 	}
 
+	// the string is printed as is, in the colors of the profile
+	cr.lipgloss.SetColorProfile(cr.profile)
+	defer cr.lipgloss.SetColorProfile(termenv.TrueColor)
+
 	cr.renderNode(vnode, 0)
 	return cr.output
 }
@@ -107,6 +134,8 @@ func (cr *InteractiveCharmRenderer) renderNode(vnode *dom.Node, depth int) {
 		cr.renderHDiv(vnode, depth)
 	case dom.ElementTypeZDiv:
 		cr.renderZDiv(vnode, depth)
+	case dom.ElementTypeScrollView, dom.ElementTypeTextArea:
+		cr.renderWithRect(vnode, depth)
 	case dom.ElementTypeSpan:
 		cr.renderSpan(vnode)
 	case dom.ElementTypeH1:
@@ -127,10 +156,10 @@ func (cr *InteractiveCharmRenderer) renderNode(vnode *dom.Node, depth int) {
 		cr.renderBr(vnode)
 	case dom.ElementTypeSpacer:
 		cr.renderSpacer(vnode, depth)
-	case dom.ElementTypeFragment:
+	case dom.ElementTypeFragment, dom.ElementTypeFocusScope, dom.ElementTypeKeyMap, dom.ElementTypeKeyHelp:
 		cr.renderFragment(vnode)
-	case "component":
-		panic("component is deprecated")
+	case dom.ElementTypeComponent:
+		cr.renderNode(vnode.RenderComponent(), depth)
 	default:
 		log.Logf("renderNode called for unknown type: %s, depth: %d", vnode.Type, depth)
 		cr.renderDefault(vnode, depth)
@@ -246,13 +275,28 @@ func (cr *InteractiveCharmRenderer) hasBlockChildren(children []*dom.Node) bool
 	return false
 }
 
+// renderWithRect renders elements that need a size, like scroll views and
+// text areas, with RenderToRect like renderZDiv
+func (cr *InteractiveCharmRenderer) renderWithRect(vnode *dom.Node, depth int) {
+	width, height := 80, 24 // Default terminal size
+	if vnode.Window != nil {
+		width, height = vnode.Window.Get()
+	}
+
+	childRenderer := &InteractiveCharmRenderer{styles: cr.styles}
+	result := childRenderer.RenderToRect(vnode, width, height)
+
+	cr.output += result.String() + "\n"
+	cr.updateRenderState(vnode.Type, true)
+}
+
 // isBlockElement checks if a node is a block element
 func (cr *InteractiveCharmRenderer) isBlockElement(node *dom.Node) bool {
 	if node == nil {
 		return false
 	}
 	return node.Type == dom.ElementTypeDiv || node.Type == dom.ElementTypeHDiv ||
-		node.Type == dom.ElementTypeZDiv ||
+		node.Type == dom.ElementTypeZDiv || node.Type == dom.ElementTypeScrollView ||
 		node.Type == dom.ElementTypeP || node.Type == dom.ElementTypeH1 ||
 		node.Type == dom.ElementTypeH2
 }
@@ -356,8 +400,9 @@ func (cr *InteractiveCharmRenderer) getNodeStyle(vnode *dom.Node) lipgloss.Style
 	var hasNodeStyle bool
 	if styleValue, ok := vnode.Props.Get("style"); ok {
 		if propStyle, ok := styleValue.(styles.Style); ok {
+			propStyle = cr.styles.theme.Resolve(propStyle)
 			if propStyle.NoDefault {
-				return domStyleToCharmStyle(lipgloss.NewStyle(), propStyle)
+				return domStyleToCharmStyle(cr.lipgloss.NewStyle(), propStyle)
 			}
 			nodeStyle = propStyle
 			hasNodeStyle = true
@@ -381,6 +426,9 @@ func (cr *InteractiveCharmRenderer) getNodeStyle(vnode *dom.Node) lipgloss.Style
 	case dom.ElementTypeText:
 		baseStyle = cr.styles.Text
 	}
+	if style, ok := cr.styles.Elements[vnode.Type]; ok {
+		baseStyle = style
+	}
 
 	if hasNodeStyle {
 		return domStyleToCharmStyle(baseStyle, nodeStyle)
@@ -437,8 +485,8 @@ func (cr *InteractiveCharmRenderer) renderInput(vnode *dom.Node) {
 	value = props.Value
 	// inputType remains "text" as there's no Type field in InputComponentProps
 
-	// Create a textinput component
-	ti := textinput.New()
+	// Create a textinput component styled to match our theme
+	ti := cr.newTextInput()
 	ti.Placeholder = placeholder
 	ti.SetValue(value)
 	ti.CharLimit = 156
@@ -472,13 +520,8 @@ func (cr *InteractiveCharmRenderer) renderInput(vnode *dom.Node) {
 		ti.EchoCharacter = '•'
 	}
 
-	// Style the textinput to match our theme
-	ti.PromptStyle = cr.styles.Prompt
-	ti.TextStyle = cr.styles.InputText
-	ti.PlaceholderStyle = cr.styles.Text.Foreground(lipgloss.Color("#626262")).Italic(true)
-
-	// Only call Focus() when the props indicate the element is focused, otherwise call Blur()
-	if props.Focused {
+	// Only call Focus() when the element is focused, otherwise call Blur()
+	if vnode.IsFocused() {
 		ti.Focus()
 	} else {
 		ti.Blur()
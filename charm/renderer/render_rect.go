@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/xhd2015/go-dom-tui/dom"
 	"github.com/xhd2015/go-dom-tui/log"
 )
@@ -16,28 +17,80 @@ import (
 // Returns a Rectangle containing the rendered output
 func (cr *InteractiveCharmRenderer) RenderToRect(vnode *dom.Node, width, height int) Rectangle {
 	if vnode == nil {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
+		return Rectangle{}
 	}
 
-	return cr.renderNodeToRect(vnode, width, height)
+	if cr.cache == nil {
+		cr.cache = newRenderCache()
+	}
+	cr.cache.begin(vnode)
+	defer cr.cache.end()
+
+	rect := cr.degrade(cr.renderNodeToRect(vnode, width, height))
+	rect.Layout = newLayout(rect.boxes)
+	return rect
 }
 
 // renderNodeToRect recursively renders a VNode into a Rectangle
 // width and height define the container dimensions available for this node
 func (cr *InteractiveCharmRenderer) renderNodeToRect(vnode *dom.Node, width, height int) Rectangle {
+	return cr.renderNodeToSize(vnode, width, height, 0)
+}
+
+// renderNodeToSize is like renderNodeToRect, with the node taking exactly
+// width and/or height on the axes in fill
+// Subtrees that did not change since the last RenderToRect are taken from the cache
+func (cr *InteractiveCharmRenderer) renderNodeToSize(vnode *dom.Node, width, height int, fill fillAxes) Rectangle {
 	if vnode == nil {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
+		return Rectangle{}
+	}
+	if cr.cache != nil {
+		if rect, ok := cr.cache.get(vnode, width, height, fill); ok {
+			return rect
+		}
+	}
+
+	margin, border, padding := cr.nodeInsets(vnode)
+	content := fitRect(cr.renderNodeContentToRect(vnode, width, height, fill), width, height, fill)
+	rect := withBox(content, vnode, margin, border, padding)
+	if cr.cache != nil {
+		cr.cache.put(vnode, width, height, fill, rect)
+	}
+	return rect
+}
+
+// nodeInsets returns the margin, border and padding the element renderer
+// draws around the content of vnode
+func (cr *InteractiveCharmRenderer) nodeInsets(vnode *dom.Node) (margin, border, padding dom.Insets) {
+	switch vnode.Type {
+	case dom.ElementTypeText, dom.ElementTypeDiv, dom.ElementTypeSpan, dom.ElementTypeH1, dom.ElementTypeP,
+		dom.ElementTypeScrollView, dom.ElementTypeTextArea:
+		return styleInsets(cr.getNodeStyle(vnode))
+	case dom.ElementTypeH2:
+		return styleInsets(cr.styles.Subtitle)
+	case dom.ElementTypeButton:
+		return styleInsets(cr.styles.Button)
+	case dom.ElementTypeInput:
+		return styleInsets(cr.styles.Input)
+	case dom.ElementTypeLi:
+		return styleInsets(cr.styles.CompactText)
 	}
+	return
+}
 
+// renderNodeContentToRect dispatches to the element specific renderer
+func (cr *InteractiveCharmRenderer) renderNodeContentToRect(vnode *dom.Node, width, height int, fill fillAxes) Rectangle {
 	switch vnode.Type {
 	case dom.ElementTypeText:
 		return cr.renderTextNodeToRect(vnode, width, height)
 	case dom.ElementTypeDiv:
-		return cr.renderContainerToRect(vnode, width, height)
+		return cr.renderContainerToRect(vnode, width, height, fill)
 	case dom.ElementTypeHDiv:
-		return cr.renderHDivToRect(vnode, width, height)
+		return cr.renderHDivToRect(vnode, width, height, fill)
 	case dom.ElementTypeZDiv:
 		return cr.renderZDivToRect(vnode, width, height)
+	case dom.ElementTypeScrollView:
+		return cr.renderScrollViewToRect(vnode, width, height, fill)
 	case dom.ElementTypeSpan:
 		return cr.renderSpanToRect(vnode, width, height)
 	case dom.ElementTypeH1:
@@ -50,6 +103,8 @@ func (cr *InteractiveCharmRenderer) renderNodeToRect(vnode *dom.Node, width, hei
 		return cr.renderButtonToRect(vnode, width, height)
 	case dom.ElementTypeInput:
 		return cr.renderInputToRect(vnode, width, height)
+	case dom.ElementTypeTextArea:
+		return cr.renderTextAreaToRect(vnode, width, height)
 	case dom.ElementTypeUl:
 		return cr.renderListToRect(vnode, width, height)
 	case dom.ElementTypeLi:
@@ -60,8 +115,10 @@ func (cr *InteractiveCharmRenderer) renderNodeToRect(vnode *dom.Node, width, hei
 		return cr.renderSpacerToRect(vnode, width, height)
 	case dom.ElementTypeFixedSpacer:
 		return cr.renderFixedSpacerToRect(vnode, width, height)
-	case dom.ElementTypeFragment:
+	case dom.ElementTypeFragment, dom.ElementTypeFocusScope, dom.ElementTypeKeyMap, dom.ElementTypeKeyHelp:
 		return cr.renderFragmentToRect(vnode, width, height)
+	case dom.ElementTypeComponent:
+		return cr.renderComponentToRect(vnode, width, height)
 	default:
 		log.Logf("renderNodeToRect called for unknown type: %s", vnode.Type)
 		return cr.renderDefaultToRect(vnode, width, height)
@@ -72,42 +129,37 @@ func (cr *InteractiveCharmRenderer) renderNodeToRect(vnode *dom.Node, width, hei
 func (cr *InteractiveCharmRenderer) renderTextNodeToRect(vnode *dom.Node, width, height int) Rectangle {
 	text := vnode.Text
 	if text == "" {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
+		return Rectangle{}
 	}
 	style := cr.getNodeStyle(vnode)
-	rendered := style.Render(text)
-	return NewRectangle(rendered)
+	return renderTextInWidth(style, text, width, textOverflowOf(vnode))
 }
 
 // renderSpanToRect renders a span element to a Rectangle
 func (cr *InteractiveCharmRenderer) renderSpanToRect(vnode *dom.Node, width, height int) Rectangle {
 	text := cr.extractRenderedText(vnode)
 	style := cr.getNodeStyle(vnode)
-	rendered := style.Render(text)
-	return NewRectangle(rendered)
+	return renderTextInWidth(style, text, width, textOverflowOf(vnode))
 }
 
 // renderTitleToRect renders an h1 element to a Rectangle
 func (cr *InteractiveCharmRenderer) renderTitleToRect(vnode *dom.Node, width, height int) Rectangle {
 	text := cr.extractRenderedText(vnode)
 	style := cr.getNodeStyle(vnode)
-	rendered := style.Render(text)
-	return NewRectangle(rendered)
+	return renderTextInWidth(style, text, width, textOverflowOf(vnode))
 }
 
 // renderSubtitleToRect renders an h2 element to a Rectangle
 func (cr *InteractiveCharmRenderer) renderSubtitleToRect(vnode *dom.Node, width, height int) Rectangle {
 	text := cr.extractRenderedText(vnode)
-	rendered := cr.styles.Subtitle.Render(text)
-	return NewRectangle(rendered)
+	return renderTextInWidth(cr.styles.Subtitle, text, width, textOverflowOf(vnode))
 }
 
 // renderTextToRect renders a p element to a Rectangle
 func (cr *InteractiveCharmRenderer) renderTextToRect(vnode *dom.Node, width, height int) Rectangle {
 	text := cr.extractRenderedText(vnode)
 	style := cr.getNodeStyle(vnode)
-	rendered := style.Render(text)
-	return NewRectangle(rendered)
+	return renderTextInWidth(style, text, width, textOverflowOf(vnode))
 }
 
 // renderButtonToRect renders a button element to a Rectangle
@@ -133,7 +185,7 @@ func (cr *InteractiveCharmRenderer) renderInputToRect(vnode *dom.Node, width, he
 	}
 	value = props.Value
 
-	ti := textinput.New()
+	ti := cr.newTextInput()
 	ti.Placeholder = placeholder
 	ti.SetValue(value)
 	ti.CharLimit = 156
@@ -159,18 +211,81 @@ func (cr *InteractiveCharmRenderer) renderInputToRect(vnode *dom.Node, width, he
 		ti.EchoCharacter = '•'
 	}
 
-	ti.PromptStyle = cr.styles.Prompt
-	ti.TextStyle = cr.styles.InputText
-	ti.PlaceholderStyle = cr.styles.Text.Foreground(lipgloss.Color("#626262")).Italic(true)
-
-	if props.Focused {
+	if vnode.IsFocused() {
 		ti.Focus()
 	} else {
 		ti.Blur()
 	}
 
 	rendered := cr.styles.Input.Render(ti.View())
-	return NewRectangle(rendered)
+	rect := NewRectangle(rendered)
+	selection := dom.TextEdit{Value: props.Value, Cursor: props.CursorPosition, Anchor: props.SelectionAnchor}
+	if start, end, ok := selection.Selection(); ok {
+		cr.highlightInputSelection(rect, ti, start, end)
+	}
+	return rect
+}
+
+// newTextInput returns a textinput in the styles of cr, drawn by its
+// lipgloss renderer down to the cursor
+func (cr *InteractiveCharmRenderer) newTextInput() textinput.Model {
+	ti := textinput.New()
+	ti.PromptStyle = cr.styles.Prompt
+	ti.TextStyle = cr.styles.InputText
+	ti.PlaceholderStyle = cr.styles.Placeholder
+	ti.CompletionStyle = cr.lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	ti.Cursor.Style = cr.lipgloss.NewStyle()
+	ti.Cursor.TextStyle = cr.lipgloss.NewStyle()
+	return ti
+}
+
+// highlightInputSelection reverses the cells of rect showing the runes
+// [start, end) of the value of ti
+func (cr *InteractiveCharmRenderer) highlightInputSelection(rect Rectangle, ti textinput.Model, start, end int) {
+	margin, border, padding := styleInsets(cr.styles.Input)
+	y := margin.Top + border.Top + padding.Top
+	if y >= rect.Height {
+		return
+	}
+	row := rect.Cells[y]
+	x := margin.Left + border.Left + padding.Left + ansi.StringWidth(ti.PromptStyle.Render(ti.Prompt))
+
+	value := []rune(ti.Value())
+	shown := 0
+	for i := textinputOffset(value, ti.Position(), ti.Width); i < len(value) && i < end; i++ {
+		w := 1
+		if ti.EchoMode == textinput.EchoNormal {
+			w = ansi.StringWidth(string(value[i]))
+		}
+		if shown+w > ti.Width {
+			break
+		}
+		if i >= start {
+			for j := x; j < x+w && j < len(row); j++ {
+				row[j].Style.Attrs |= AttrReverse
+			}
+		}
+		x += w
+		shown += w
+	}
+}
+
+// textinputOffset returns the first rune of value a textinput of the given
+// width shows after SetValue then SetCursor(pos), as renderInputToRect does:
+// SetValue scrolls to the end of the value, and SetCursor back to pos
+func textinputOffset(value []rune, pos, width int) int {
+	if width <= 0 || ansi.StringWidth(string(value)) <= width {
+		return 0
+	}
+	w := 0
+	i := len(value) - 1
+	for i > 0 && w < width {
+		w += ansi.StringWidth(string(value[i]))
+		if w <= width {
+			i--
+		}
+	}
+	return min(pos, i+1)
 }
 
 // renderListToRect renders a ul element to a Rectangle
@@ -183,6 +298,9 @@ func (cr *InteractiveCharmRenderer) renderListToRect(vnode *dom.Node, width, hei
 		if remainingHeight <= 0 {
 			break
 		}
+		if child == nil {
+			continue
+		}
 		// Handle FixedSpacer specially for vertical layout
 		if child.Type == dom.ElementTypeFixedSpacer {
 			childRect := cr.renderFixedSpacerForVertical(child)
@@ -200,7 +318,7 @@ func (cr *InteractiveCharmRenderer) renderListToRect(vnode *dom.Node, width, hei
 	}
 
 	if len(childRects) == 0 {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
+		return Rectangle{}
 	}
 
 	return stackVertically(childRects)
@@ -233,7 +351,7 @@ func (cr *InteractiveCharmRenderer) renderListItemToRect(vnode *dom.Node, width,
 
 // renderBrToRect renders a br element to a Rectangle
 func (cr *InteractiveCharmRenderer) renderBrToRect(vnode *dom.Node, width, height int) Rectangle {
-	return Rectangle{Width: 0, Height: 1, Lines: []string{""}}
+	return NewEmptyRectangle(0, 1)
 }
 
 // renderSpacerToRect renders a spacer element to a Rectangle
@@ -243,7 +361,7 @@ func (cr *InteractiveCharmRenderer) renderSpacerToRect(vnode *dom.Node, width, h
 	if minSize <= 0 {
 		minSize = 1
 	}
-	return Rectangle{Width: minSize, Height: 1, Lines: []string{strings.Repeat(" ", minSize)}}
+	return NewEmptyRectangle(minSize, 1)
 }
 
 // renderFixedSpacerToRect renders a fixed spacer element to a Rectangle
@@ -261,7 +379,7 @@ func (cr *InteractiveCharmRenderer) renderFixedSpacerForHorizontal(vnode *dom.No
 	if space <= 0 {
 		space = 1
 	}
-	return Rectangle{Width: space, Height: 1, Lines: []string{strings.Repeat(" ", space)}}
+	return NewEmptyRectangle(space, 1)
 }
 
 // renderFixedSpacerForVertical renders a fixed spacer for vertical layout (Div, Fragment, Ul)
@@ -272,11 +390,7 @@ func (cr *InteractiveCharmRenderer) renderFixedSpacerForVertical(vnode *dom.Node
 	if space <= 0 {
 		space = 1
 	}
-	lines := make([]string, space)
-	for i := 0; i < space; i++ {
-		lines[i] = ""
-	}
-	return Rectangle{Width: 0, Height: space, Lines: lines}
+	return NewEmptyRectangle(0, space)
 }
 
 // renderFragmentToRect renders a fragment to a Rectangle
@@ -289,6 +403,9 @@ func (cr *InteractiveCharmRenderer) renderFragmentToRect(vnode *dom.Node, width,
 		if remainingHeight <= 0 {
 			break
 		}
+		if child == nil {
+			continue
+		}
 		// Handle FixedSpacer specially for vertical layout
 		if child.Type == dom.ElementTypeFixedSpacer {
 			childRect := cr.renderFixedSpacerForVertical(child)
@@ -306,123 +423,209 @@ func (cr *InteractiveCharmRenderer) renderFragmentToRect(vnode *dom.Node, width,
 	}
 
 	if len(childRects) == 0 {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
+		return Rectangle{}
 	}
 
 	return stackVertically(childRects)
 }
 
-// renderContainerToRect renders a container div to a Rectangle
-// Vertical layout: width stays same for all children, height decreases as children are rendered
-func (cr *InteractiveCharmRenderer) renderContainerToRect(vnode *dom.Node, width, height int) Rectangle {
-	var childRects []Rectangle
-	remainingHeight := height
-
-	for _, child := range vnode.Children {
-		if remainingHeight <= 0 {
-			break
-		}
-		// Handle FixedSpacer specially for vertical layout
-		if child.Type == dom.ElementTypeFixedSpacer {
-			childRect := cr.renderFixedSpacerForVertical(child)
-			if childRect.Height > 0 {
-				childRects = append(childRects, childRect)
-				remainingHeight -= childRect.Height
-			}
-		} else {
-			childRect := cr.renderNodeToRect(child, width, remainingHeight)
-			if childRect.Height > 0 {
-				childRects = append(childRects, childRect)
-				remainingHeight -= childRect.Height
-			}
-		}
+// renderComponentToRect renders a component node as a transparent wrapper of its output
+func (cr *InteractiveCharmRenderer) renderComponentToRect(vnode *dom.Node, width, height int) Rectangle {
+	out := vnode.RenderComponent()
+	if out == nil {
+		return Rectangle{}
 	}
+	return cr.renderNodeToRect(out, width, height)
+}
 
-	var contentRect Rectangle
-	if len(childRects) == 0 {
-		contentRect = Rectangle{Width: 0, Height: 0, Lines: []string{}}
-	} else {
-		contentRect = stackVertically(childRects)
-	}
+// renderContainerToRect renders a container div to a Rectangle
+// Vertical flex layout: children are stacked top to bottom inside the
+// margin, border and padding of the div
+func (cr *InteractiveCharmRenderer) renderContainerToRect(vnode *dom.Node, width, height int, fill fillAxes) Rectangle {
+	width, height, fill = fixedSize(vnode, width, height, fill)
+	style := cr.getNodeStyle(vnode)
+	margin, border, padding := styleInsets(style)
+	innerWidth := width - margin.Left - margin.Right - border.Left - border.Right - padding.Left - padding.Right
+	innerHeight := height - margin.Top - margin.Bottom - border.Top - border.Bottom - padding.Top - padding.Bottom
+
+	contentRect := cr.renderFlexToRect(vnode, flexColumn, innerWidth, innerHeight, fill)
 
 	// Apply style (border, padding, etc.)
-	style := cr.getNodeStyle(vnode)
-	rendered := style.Render(contentRect.String())
-	return NewRectangle(rendered)
+	return styleRect(style, contentRect)
 }
 
-// renderHDivToRect renders an HDiv (horizontal layout) to a Rectangle
-// Horizontal layout: height stays same for all children, width decreases as children are rendered
-func (cr *InteractiveCharmRenderer) renderHDivToRect(vnode *dom.Node, width, height int) Rectangle {
-	var childRects []Rectangle
-	remainingWidth := width
+// fixedSize applies the Width and Height props of a div, which it
+// takes whatever space its parent gives
+func fixedSize(vnode *dom.Node, width, height int, fill fillAxes) (int, int, fillAxes) {
+	p, ok := vnode.Props.(dom.StructProps[dom.DivProps])
+	if !ok {
+		return width, height, fill
+	}
+	if p.Value.Width > 0 {
+		width = p.Value.Width
+		fill |= fillWidth
+	}
+	if p.Value.Height > 0 {
+		height = p.Value.Height
+		fill |= fillHeight
+	}
+	return width, height, fill
+}
 
-	for _, child := range vnode.Children {
-		if remainingWidth <= 0 {
-			break
+// styleRect draws content inside the margin, border and padding of style,
+// with the colors and attributes of style applied to the cells that do not
+// set their own
+func styleRect(style lipgloss.Style, content Rectangle) Rectangle {
+	if style.GetInline() {
+		// inline styles reflow their content, leave it to lipgloss
+		return NewRectangle(style.Render(content.String()))
+	}
+
+	if content.Height == 0 {
+		// like lipgloss, render nothing as one empty line
+		boxes := content.boxes
+		content = NewEmptyRectangle(content.Width, 1)
+		content.boxes = boxes
+	}
+
+	margin, border, padding := styleInsets(style)
+	x := margin.Left + border.Left + padding.Left
+	y := margin.Top + border.Top + padding.Top
+	var frame Rectangle
+	if x == 0 && y == 0 && margin.Right+border.Right+padding.Right == 0 &&
+		margin.Bottom+border.Bottom+padding.Bottom == 0 && style.GetWidth() == 0 && style.GetHeight() == 0 {
+		cellStyle := cellStyleOf(style)
+		if cellStyle == (CellStyle{}) {
+			// nothing to draw
+			return content
 		}
-		// Handle FixedSpacer specially for horizontal layout
-		if child.Type == dom.ElementTypeFixedSpacer {
-			childRect := cr.renderFixedSpacerForHorizontal(child)
-			if childRect.Width > 0 {
-				childRects = append(childRects, childRect)
-				remainingWidth -= childRect.Width
-			}
-		} else {
-			childRect := cr.renderNodeToRect(child, remainingWidth, height)
-			if childRect.Width > 0 {
-				childRects = append(childRects, childRect)
-				remainingWidth -= childRect.Width
+		frame = NewEmptyRectangle(content.Width, content.Height)
+		for _, row := range frame.Cells {
+			for i := range row {
+				row[i].Style = cellStyle
 			}
 		}
+	} else {
+		// let lipgloss draw the decoration around a blank content area
+		blank := make([]string, content.Height)
+		for i := range blank {
+			blank[i] = strings.Repeat(" ", content.Width)
+		}
+		frame = NewRectangle(style.Render(strings.Join(blank, "\n")))
 	}
+	drawRect(frame.Cells, content, x, y, true)
+	frame.boxes = translateBoxes(content.boxes, x, y)
+	return frame
+}
 
-	if len(childRects) == 0 {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
-	}
-
-	props := dom.ExtractProps[dom.DivProps](vnode.Props)
-	return stackHorizontally(childRects, props.Align)
+// renderHDivToRect renders an HDiv (horizontal layout) to a Rectangle
+// Horizontal flex layout: children are placed left to right
+func (cr *InteractiveCharmRenderer) renderHDivToRect(vnode *dom.Node, width, height int, fill fillAxes) Rectangle {
+	width, height, fill = fixedSize(vnode, width, height, fill)
+	return cr.renderFlexToRect(vnode, flexRow, width, height, fill)
 }
 
 // renderZDivToRect renders a ZDiv (z-order overlay) to a Rectangle
-// Overlay layout: dimensions stay the same for all children
+// Overlay layout: children are drawn over each other, at the top-left
+// corner unless their Position places them elsewhere
 func (cr *InteractiveCharmRenderer) renderZDivToRect(vnode *dom.Node, width, height int) Rectangle {
 	if len(vnode.Children) == 0 {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
+		return Rectangle{}
+	}
+
+	type zChild struct {
+		rect Rectangle
+		pos  dom.Position
 	}
 
-	// Render each child to a Rectangle with the same dimensions
-	childRects := make([]Rectangle, 0, len(vnode.Children))
+	// Render each child to a Rectangle within the space its offsets leave
+	children := make([]zChild, 0, len(vnode.Children))
+	boxWidth, boxHeight := 0, 0
 	for _, child := range vnode.Children {
 		// Skip FixedSpacer in ZDiv (no effect)
-		if child.Type == dom.ElementTypeFixedSpacer {
+		if child == nil || child.Type == dom.ElementTypeFixedSpacer {
+			continue
+		}
+		pos := dom.PositionOf(child.Props)
+		childWidth := width - offsetValue(pos.Left) - offsetValue(pos.Right)
+		childHeight := height - offsetValue(pos.Top) - offsetValue(pos.Bottom)
+		childRect := cr.renderNodeToRect(child, max(childWidth, 0), max(childHeight, 0))
+		if childRect.Height == 0 && childRect.Width == 0 {
 			continue
 		}
-		childRect := cr.renderNodeToRect(child, width, height)
-		if childRect.Height > 0 || childRect.Width > 0 {
-			childRects = append(childRects, childRect)
+		children = append(children, zChild{rect: childRect, pos: pos})
+
+		// children placed from the right or bottom edge, or centered,
+		// need the ZDiv to take all the space it is given
+		if pos.CenterX || (pos.Right != nil && pos.Left == nil) {
+			boxWidth = width
+		}
+		if (pos.CenterY || (pos.Bottom != nil && pos.Top == nil)) && height < unbounded {
+			boxHeight = height
 		}
+		boxWidth = max(boxWidth, min(offsetValue(pos.Left)+childRect.Width, width))
+		boxHeight = max(boxHeight, min(offsetValue(pos.Top)+childRect.Height, height))
 	}
 
-	if len(childRects) == 0 {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
+	if len(children) == 0 {
+		return Rectangle{}
 	}
 
-	// Start with the first child as the base
-	result := childRects[0]
-
-	// Overlay each subsequent child onto the result
-	for i := 1; i < len(childRects); i++ {
-		result = Overlay(result, childRects[i])
+	// Overlay each child onto the previous ones, later children on top
+	result := NewEmptyRectangle(boxWidth, boxHeight)
+	for _, child := range children {
+		x := placeOnAxis(child.pos.Left, child.pos.Right, child.pos.CenterX, child.rect.Width, boxWidth)
+		y := placeOnAxis(child.pos.Top, child.pos.Bottom, child.pos.CenterY, child.rect.Height, boxHeight)
+		result = OverlayAt(result, clipRect(child.rect, boxWidth-x, boxHeight-y), x, y)
 	}
 
 	return result
 }
 
+// placeOnAxis returns where a child of the given size starts inside
+// a ZDiv of the given size, along one axis
+func placeOnAxis(start, end *int, center bool, size, boxSize int) int {
+	switch {
+	case center:
+		return (boxSize-size)/2 + offsetValue(start) - offsetValue(end)
+	case start != nil:
+		return *start
+	case end != nil:
+		return boxSize - size - *end
+	}
+	return 0
+}
+
+func offsetValue(offset *int) int {
+	if offset == nil {
+		return 0
+	}
+	return *offset
+}
+
+// clipRect cuts the cells of rect beyond width and height,
+// keeping its boxes so the layout still knows the nodes it draws
+func clipRect(rect Rectangle, width, height int) Rectangle {
+	width, height = max(width, 0), max(height, 0)
+	if rect.Width <= width && rect.Height <= height {
+		return rect
+	}
+	rows := rect.Cells[:min(rect.Height, height)]
+	clipped := make([][]Cell, len(rows))
+	for i, row := range rows {
+		clipped[i] = row[:min(len(row), width)]
+	}
+	return Rectangle{
+		Width:  min(rect.Width, width),
+		Height: len(clipped),
+		Cells:  clipped,
+		boxes:  rect.boxes,
+	}
+}
+
 // renderDefaultToRect renders unknown elements to a Rectangle
 func (cr *InteractiveCharmRenderer) renderDefaultToRect(vnode *dom.Node, width, height int) Rectangle {
-	lines := []string{fmt.Sprintf("<%s>", vnode.Type)}
+	rects := []Rectangle{NewRectangle(fmt.Sprintf("<%s>", vnode.Type))}
 	remainingHeight := height - 2 // Reserve space for opening and closing tags
 
 	for _, child := range vnode.Children {
@@ -430,19 +633,18 @@ func (cr *InteractiveCharmRenderer) renderDefaultToRect(vnode *dom.Node, width,
 			break
 		}
 		childRect := cr.renderNodeToRect(child, width, remainingHeight)
-		lines = append(lines, childRect.Lines...)
+		rects = append(rects, childRect)
 		remainingHeight -= childRect.Height
 	}
-	lines = append(lines, fmt.Sprintf("</%s>", vnode.Type))
+	rects = append(rects, NewRectangle(fmt.Sprintf("</%s>", vnode.Type)))
 
-	content := strings.Join(lines, "\n")
-	return NewRectangle(content)
+	return stackVertically(rects)
 }
 
 // stackVertically stacks rectangles vertically
 func stackVertically(rects []Rectangle) Rectangle {
 	if len(rects) == 0 {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
+		return Rectangle{}
 	}
 
 	// Calculate total height and max width
@@ -455,110 +657,21 @@ func stackVertically(rects []Rectangle) Rectangle {
 		}
 	}
 
-	// Combine all lines
-	var allLines []string
+	// Put all rows below each other, sharing those already wide enough
+	cells := make([][]Cell, 0, totalHeight)
+	var boxes []*layoutBox
 	for _, rect := range rects {
-		allLines = append(allLines, rect.Lines...)
+		boxes = append(boxes, translateBoxes(rect.boxes, 0, len(cells))...)
+		for _, row := range rect.Cells {
+			cells = append(cells, padRow(row, maxWidth))
+		}
 	}
 
 	return Rectangle{
 		Width:  maxWidth,
 		Height: totalHeight,
-		Lines:  allLines,
-	}
-}
-
-// stackHorizontally stacks rectangles horizontally
-func stackHorizontally(rects []Rectangle, align dom.Align) Rectangle {
-	if len(rects) == 0 {
-		return Rectangle{Width: 0, Height: 0, Lines: []string{}}
-	}
-
-	// Calculate total width and max height
-	totalWidth := 0
-	maxHeight := 0
-	for _, rect := range rects {
-		totalWidth += rect.Width
-		if rect.Height > maxHeight {
-			maxHeight = rect.Height
-		}
-	}
-
-	// Pad all rectangles to the same height with proper alignment
-	paddedRects := make([]Rectangle, len(rects))
-	for i, rect := range rects {
-		if rect.Height < maxHeight {
-			paddedRects[i] = padRectangleVertically(rect, maxHeight, align)
-		} else {
-			paddedRects[i] = rect
-		}
-	}
-
-	// Combine lines horizontally
-	resultLines := make([]string, maxHeight)
-	for i := 0; i < maxHeight; i++ {
-		var lineParts []string
-		for _, rect := range paddedRects {
-			lineParts = append(lineParts, rect.Lines[i])
-		}
-		resultLines[i] = strings.Join(lineParts, "")
-	}
-
-	return Rectangle{
-		Width:  totalWidth,
-		Height: maxHeight,
-		Lines:  resultLines,
-	}
-}
-
-// padRectangleVertically pads a rectangle to the target height with proper vertical alignment
-func padRectangleVertically(rect Rectangle, targetHeight int, align dom.Align) Rectangle {
-	if rect.Height >= targetHeight {
-		return rect
-	}
-
-	paddingNeeded := targetHeight - rect.Height
-	paddedLines := make([]string, targetHeight)
-	emptyLine := strings.Repeat(" ", rect.Width)
-
-	switch align {
-	case dom.AlignTop, "": // Default to top alignment
-		// Content at top, padding at bottom
-		copy(paddedLines, rect.Lines)
-		for j := rect.Height; j < targetHeight; j++ {
-			paddedLines[j] = emptyLine
-		}
-
-	case dom.AlignBottom:
-		// Padding at top, content at bottom
-		for j := 0; j < paddingNeeded; j++ {
-			paddedLines[j] = emptyLine
-		}
-		copy(paddedLines[paddingNeeded:], rect.Lines)
-
-	case dom.AlignCenter:
-		// Padding distributed top and bottom
-		topPadding := paddingNeeded / 2
-		for j := 0; j < topPadding; j++ {
-			paddedLines[j] = emptyLine
-		}
-		copy(paddedLines[topPadding:], rect.Lines)
-		for j := topPadding + rect.Height; j < targetHeight; j++ {
-			paddedLines[j] = emptyLine
-		}
-
-	default:
-		// Unknown alignment, default to top
-		copy(paddedLines, rect.Lines)
-		for j := rect.Height; j < targetHeight; j++ {
-			paddedLines[j] = emptyLine
-		}
-	}
-
-	return Rectangle{
-		Width:  rect.Width,
-		Height: targetHeight,
-		Lines:  paddedLines,
+		Cells:  cells,
+		boxes:  boxes,
 	}
 }
 
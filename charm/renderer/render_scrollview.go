@@ -0,0 +1,135 @@
+package renderer
+
+import (
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// scrollRow is a rendered child of a scroll view, y is its top line in the viewport
+type scrollRow struct {
+	rect Rectangle
+	y    int
+}
+
+// renderScrollViewToRect renders the children of a scroll view stacked
+// top to bottom, and keeps the lines from ScrollOffset that fit the viewport.
+// With an ItemHeight only the children in view are rendered.
+func (cr *InteractiveCharmRenderer) renderScrollViewToRect(vnode *dom.Node, width, height int, fill fillAxes) Rectangle {
+	props := dom.ExtractProps[dom.ScrollViewProps](vnode.Props)
+	if props.Height > 0 {
+		height = props.Height
+		fill |= fillHeight
+	}
+	style := cr.getNodeStyle(vnode)
+	margin, border, padding := styleInsets(style)
+	innerWidth := max(width-margin.Left-margin.Right-border.Left-border.Right-padding.Left-padding.Right, 0)
+	innerHeight := max(height-margin.Top-margin.Bottom-border.Top-border.Bottom-padding.Top-padding.Bottom, 0)
+
+	contentWidth := innerWidth
+	if props.Scrollbar && contentWidth > 0 {
+		contentWidth--
+	}
+
+	var rows []scrollRow
+	var contentHeight, viewportHeight, offset int
+	viewport := func(total int) {
+		contentHeight = total
+		viewportHeight = innerHeight
+		if fill&fillHeight == 0 {
+			viewportHeight = min(total, innerHeight)
+		}
+		offset = min(max(props.ScrollOffset, 0), max(total-viewportHeight, 0))
+	}
+
+	if props.ItemHeight > 0 {
+		viewport(len(vnode.Children) * props.ItemHeight)
+		for i := offset / props.ItemHeight; i < len(vnode.Children) && i*props.ItemHeight < offset+viewportHeight; i++ {
+			child := vnode.Children[i]
+			if child == nil {
+				continue
+			}
+			rect := fitRect(cr.renderNodeToRect(child, contentWidth, props.ItemHeight), contentWidth, props.ItemHeight, fillHeight)
+			rows = append(rows, scrollRow{rect: rect, y: i*props.ItemHeight - offset})
+		}
+	} else {
+		// measure the whole content
+		total := 0
+		for _, child := range vnode.Children {
+			if child == nil {
+				continue
+			}
+			var rect Rectangle
+			if child.Type == dom.ElementTypeFixedSpacer {
+				rect = cr.renderFixedSpacerForVertical(child)
+			} else {
+				rect = cr.renderNodeToRect(child, contentWidth, unbounded)
+			}
+			rows = append(rows, scrollRow{rect: rect, y: total})
+			total += rect.Height
+		}
+		viewport(total)
+		visible := rows[:0]
+		for _, row := range rows {
+			row.y -= offset
+			if row.y < viewportHeight && row.y+row.rect.Height > 0 {
+				visible = append(visible, row)
+			}
+		}
+		rows = visible
+	}
+
+	content := composeScrollRows(rows, contentWidth, innerWidth, viewportHeight)
+	if props.Scrollbar && contentWidth < innerWidth && contentHeight > viewportHeight {
+		drawScrollbar(content.Cells, innerWidth-1, offset, viewportHeight, contentHeight)
+	}
+
+	rect := styleRect(style, content)
+	rect.scroll = &scrollBox{offset: offset, contentHeight: contentHeight}
+	return rect
+}
+
+// composeScrollRows draws the rows of a scroll view into a viewport of
+// the given size, cutting the rows that are partly scrolled out
+func composeScrollRows(rows []scrollRow, contentWidth, width, height int) Rectangle {
+	cells := make([][]Cell, height)
+	var boxes []*layoutBox
+	for _, row := range rows {
+		for ry, line := range row.rect.Cells {
+			y := row.y + ry
+			if y < 0 || y >= height {
+				continue
+			}
+			cells[y] = padRow(line[:min(len(line), contentWidth)], width)
+		}
+		boxes = append(boxes, translateBoxes(row.rect.boxes, 0, row.y)...)
+	}
+	var blank []Cell
+	for y := range cells {
+		if cells[y] != nil {
+			continue
+		}
+		if width > contentWidth {
+			// the scrollbar column is drawn into each row
+			cells[y] = newGrid(width, 1)[0]
+			continue
+		}
+		if blank == nil {
+			blank = newGrid(width, 1)[0]
+		}
+		cells[y] = blank
+	}
+	return Rectangle{Width: width, Height: height, Cells: cells, boxes: boxes}
+}
+
+// drawScrollbar draws a track down column x with a thumb sized and placed
+// like the viewport over the content
+func drawScrollbar(cells [][]Cell, x, offset, viewportHeight, contentHeight int) {
+	thumb := max(viewportHeight*viewportHeight/contentHeight, 1)
+	top := offset * (viewportHeight - thumb) / (contentHeight - viewportHeight)
+	for y, row := range cells {
+		content := "│"
+		if y >= top && y < top+thumb {
+			content = "█"
+		}
+		row[x] = Cell{Content: content, Width: 1}
+	}
+}
@@ -0,0 +1,154 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+func lines(n int) []*dom.Node {
+	nodes := make([]*dom.Node, n)
+	for i := range nodes {
+		nodes[i] = dom.Text(fmt.Sprintf("line %d", i))
+	}
+	return nodes
+}
+
+func TestScrollView(t *testing.T) {
+	tests := []struct {
+		name     string
+		props    dom.ScrollViewProps
+		children []*dom.Node
+		expected string
+	}{
+		{
+			name:     "Top",
+			props:    dom.ScrollViewProps{Height: 3},
+			children: lines(5),
+			expected: "line 0  \nline 1  \nline 2  ",
+		},
+		{
+			name:     "Offset",
+			props:    dom.ScrollViewProps{Height: 3, ScrollOffset: 1},
+			children: lines(5),
+			expected: "line 1  \nline 2  \nline 3  ",
+		},
+		{
+			name:     "OffsetClampedToContent",
+			props:    dom.ScrollViewProps{Height: 3, ScrollOffset: 9},
+			children: lines(5),
+			expected: "line 2  \nline 3  \nline 4  ",
+		},
+		{
+			name:     "ItemHeight",
+			props:    dom.ScrollViewProps{Height: 3, ItemHeight: 1, ScrollOffset: 2},
+			children: lines(5),
+			expected: "line 2  \nline 3  \nline 4  ",
+		},
+		{
+			name:  "TallChildCut",
+			props: dom.ScrollViewProps{Height: 3, ScrollOffset: 1},
+			children: []*dom.Node{
+				dom.Div(dom.DivProps{}, dom.Text("a1"), dom.Text("a2")),
+				dom.Div(dom.DivProps{}, dom.Text("b1"), dom.Text("b2")),
+			},
+			expected: "a2      \nb1      \nb2      ",
+		},
+		{
+			name:     "Scrollbar",
+			props:    dom.ScrollViewProps{Height: 2, Scrollbar: true, ScrollOffset: 2},
+			children: lines(4),
+			expected: "line 2 │\nline 3 █",
+		},
+		{
+			name:     "ShortContent",
+			props:    dom.ScrollViewProps{},
+			children: lines(2),
+			expected: "line 0  \nline 1  ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rect := NewInteractiveCharmRenderer().RenderToRect(dom.ScrollView(tt.props, tt.children...), 8, 10)
+			if got := StripColor(rect.String()); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestScrollViewRendersOnlyVisibleItems tests that long lists with an
+// ItemHeight leave the children out of view unrendered
+func TestScrollViewRendersOnlyVisibleItems(t *testing.T) {
+	items := make([]*dom.Node, 10000)
+	for i := range items {
+		items[i] = dom.Text(fmt.Sprintf("item %d", i))
+	}
+	view := dom.ScrollView(dom.ScrollViewProps{Height: 4, ItemHeight: 1, ScrollOffset: 5000}, items...)
+
+	rect := NewInteractiveCharmRenderer().RenderToRect(view, 20, 10)
+
+	rendered := 0
+	for _, item := range items {
+		if _, ok := rect.Layout.BoundsOf(item); ok {
+			rendered++
+		}
+	}
+	if rendered != 4 {
+		t.Errorf("expected 4 items rendered, got %d", rendered)
+	}
+	if got, expected := StripColor(rect.String()), "item 5000           \nitem 5001           \nitem 5002           \nitem 5003           "; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+// TestScrollViewMeasuresMemoItemsFromCache tests that without an ItemHeight,
+// items marked with Memo are measured from the output of the last frame
+func TestScrollViewMeasuresMemoItemsFromCache(t *testing.T) {
+	list := func() *dom.Node {
+		items := make([]*dom.Node, 100)
+		for i := range items {
+			items[i] = dom.Memo(dom.Text(fmt.Sprintf("item %d", i)), i)
+		}
+		return dom.Div(dom.DivProps{}, dom.ScrollView(dom.ScrollViewProps{Height: 4, ScrollOffset: 50}, items...))
+	}
+	cr := NewInteractiveCharmRenderer()
+	renderFrame(cr, list(), 20, 10)
+	cr.cache.hits, cr.cache.misses = 0, 0
+	rect := renderFrame(cr, list(), 20, 10)
+
+	if cr.cache.hits != 100 || cr.cache.misses != 0 {
+		t.Errorf("expected the 100 items taken from the cache, got %d hits and %d misses", cr.cache.hits, cr.cache.misses)
+	}
+	if got := StripColor(rect.String()); !strings.HasPrefix(got, "item 50") {
+		t.Errorf("expected the view to start at item 50, got %q", got)
+	}
+}
+
+func TestScrollViewLayout(t *testing.T) {
+	items := lines(6)
+	view := dom.ScrollView(dom.ScrollViewProps{ScrollOffset: 2}, items...)
+	root := dom.Div(dom.DivProps{Height: 4}, dom.Text("header"), view)
+
+	rect := NewInteractiveCharmRenderer().RenderToRect(root, 10, 10)
+
+	expected := "header    \nline 2    \nline 3    \nline 4    "
+	if got := StripColor(rect.String()); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	extent, ok := rect.Layout.ScrollExtentOf(view)
+	if !ok {
+		t.Fatalf("expected scroll extent of the view")
+	}
+	if extent.Viewport != (dom.Rect{Y: 1, Width: 10, Height: 3}) || extent.Offset != 2 || extent.ContentHeight != 6 {
+		t.Errorf("unexpected extent %+v", extent)
+	}
+	if node := rect.Layout.NodeAt(0, 1); node != items[2] {
+		t.Errorf("expected line 2 at the top of the view, got %v", node)
+	}
+	if node := rect.Layout.NodeAt(0, 0); node == items[1] {
+		t.Errorf("expected line 1, scrolled out, not to be hit")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/react"
 )
 
 // renderNodeHelper is a helper function that takes a *dom.Node and returns the rendered string
@@ -476,3 +477,47 @@ func TestMultiLineTextInDiv(t *testing.T) {
 		}
 	})
 }
+
+// TestRenderComponent tests rendering component nodes expanded by react
+func TestRenderComponent(t *testing.T) {
+	greeting := func(props dom.Props) *dom.Node {
+		return dom.Div(dom.DivProps{}, dom.Text("Hello "+dom.GetStringProp(props, "text")))
+	}
+	vnode := dom.Div(dom.DivProps{},
+		dom.CreateComponent(greeting, dom.NewStructProps(dom.ButtonProps{Text: "World"})),
+	)
+	root := react.NewReact(nil).Render(func() *dom.Node { return vnode })
+
+	rect := NewInteractiveCharmRenderer().RenderToRect(root, 20, 5)
+	output := StripColor(rect.String())
+
+	expected := "Hello World"
+	if output != expected {
+		t.Errorf("Expected exact output:\n%q\nGot:\n%q", expected, output)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a component node not expanded by react to panic")
+		}
+	}()
+	NewInteractiveCharmRenderer().RenderToRect(vnode, 20, 5)
+}
+
+func TestRenderInputSelection(t *testing.T) {
+	anchor := 1
+	props := dom.InputProps{Value: "hello", CursorPosition: 3, SelectionAnchor: &anchor, Width: 10}
+	rect := NewInteractiveCharmRenderer().RenderToRect(dom.Input(props), 20, 3)
+
+	var reversed string
+	for _, row := range rect.Cells {
+		for _, c := range row {
+			if c.Style.Attrs&AttrReverse != 0 {
+				reversed += c.Content
+			}
+		}
+	}
+	if reversed != "el" {
+		t.Errorf("expected the selection %q reversed, got %q", "el", reversed)
+	}
+}
@@ -0,0 +1,185 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+// textRow is a row of a text area on screen: the runes [start, end)
+// of the value, which belong to the line numbered line
+type textRow struct {
+	line  int
+	start int
+	end   int
+	first bool // first row of its line, which shows the line number
+}
+
+// renderTextAreaToRect renders a text area to a Rectangle
+// Lines wrap at word boundaries unless NoWrap is set, and the view
+// follows the cursor when the text does not fit
+func (cr *InteractiveCharmRenderer) renderTextAreaToRect(vnode *dom.Node, width, height int) Rectangle {
+	props := dom.ExtractProps[dom.TextAreaProps](vnode.Props)
+	if props.Width > 0 {
+		width = props.Width
+	}
+	style := cr.getNodeStyle(vnode)
+	margin, border, padding := styleInsets(style)
+	innerWidth := max(width-margin.Left-margin.Right-border.Left-border.Right-padding.Left-padding.Right, 1)
+	innerHeight := height - margin.Top - margin.Bottom - border.Top - border.Bottom - padding.Top - padding.Bottom
+
+	runes := []rune(props.Value)
+	cursor := min(max(props.CursorPosition, 0), len(runes))
+	focused := vnode.IsFocused()
+
+	gutter := 0
+	if props.LineNumbers {
+		gutter = len(fmt.Sprint(strings.Count(props.Value, "\n")+1)) + 1
+	}
+	textWidth := max(innerWidth-gutter, 1)
+
+	rows := wrapTextRows(runes, textWidth, !props.NoWrap, cursor)
+	current := 0
+	for i, row := range rows {
+		if row.start <= cursor {
+			current = i
+		}
+	}
+
+	visible := len(rows)
+	if props.Height > 0 {
+		visible = props.Height
+	}
+	if innerHeight > 0 {
+		visible = min(visible, innerHeight)
+	}
+	top := max(current-visible+1, 0)
+
+	// with NoWrap, scroll horizontally to keep the cursor in view
+	scrollX := 0
+	if props.NoWrap {
+		if col := ansi.StringWidth(string(runes[rows[current].start:cursor])); col >= textWidth {
+			scrollX = col - textWidth + 1
+		}
+	}
+
+	selStart, selEnd, selected := dom.TextEdit{Value: props.Value, Cursor: cursor, Anchor: props.SelectionAnchor}.Selection()
+	cells := newGrid(innerWidth, visible)
+	for y := range cells {
+		i := top + y
+		if i >= len(rows) {
+			break
+		}
+		row := rows[i]
+		line := cells[y]
+		if gutter > 0 && row.first {
+			number := fmt.Sprintf("%*d", gutter-1, row.line+1)
+			for x, r := range number {
+				line[x] = Cell{Content: string(r), Width: 1, Style: CellStyle{Attrs: AttrFaint}}
+			}
+		}
+
+		x := -scrollX
+		for p := row.start; p < row.end; p++ {
+			content, w := textCell(runes[p])
+			var cellStyle CellStyle
+			if selected && p >= selStart && p < selEnd {
+				cellStyle.Attrs |= AttrReverse
+			}
+			if focused && p == cursor {
+				cellStyle.Attrs ^= AttrReverse
+			}
+			if w > 0 && x >= 0 && x+w <= textWidth {
+				setCell(line, gutter+x, Cell{Content: content, Width: w, Style: cellStyle})
+			}
+			x += w
+		}
+		if focused && i == current && cursor == row.end && x >= 0 && x < textWidth {
+			// the cursor after the last rune of the line
+			line[gutter+x].Style.Attrs |= AttrReverse
+		}
+	}
+
+	if props.Value == "" && props.Placeholder != "" && visible > 0 {
+		x := 0
+		for _, r := range props.Placeholder {
+			content, w := textCell(r)
+			if x+w > textWidth {
+				break
+			}
+			if w > 0 {
+				setCell(cells[0], gutter+x, Cell{Content: content, Width: w, Style: CellStyle{Attrs: AttrFaint}})
+			}
+			x += w
+		}
+		if focused {
+			cells[0][gutter].Style.Attrs |= AttrReverse
+		}
+	}
+
+	return styleRect(style, Rectangle{Width: innerWidth, Height: visible, Cells: cells})
+}
+
+// textCell returns what a rune of a text area draws and its width,
+// tabs are drawn as a space
+func textCell(r rune) (string, int) {
+	if r == '\t' {
+		return " ", 1
+	}
+	s := string(r)
+	return s, ansi.StringWidth(s)
+}
+
+// wrapTextRows splits the lines of runes into rows of at most width
+// cells, breaking after the last space that fits or, in words longer
+// than a row, at the width. Without wrap each line is one row.
+// A line filling its last row gets an empty row when the cursor is at
+// its end, so that the cursor can be shown.
+func wrapTextRows(runes []rune, width int, wrap bool, cursor int) []textRow {
+	var rows []textRow
+	line := 0
+	for start := 0; ; {
+		end := start
+		for end < len(runes) && runes[end] != '\n' {
+			end++
+		}
+		if !wrap {
+			rows = append(rows, textRow{line: line, start: start, end: end, first: true})
+		} else {
+			pos := start
+			for first := true; first || pos < end; first = false {
+				rowStart := pos
+				w := 0
+				lastBreak := -1
+				for pos < end {
+					_, rw := textCell(runes[pos])
+					if w+rw > width {
+						break
+					}
+					w += rw
+					pos++
+					if runes[pos-1] == ' ' {
+						lastBreak = pos
+					}
+				}
+				if pos < end && lastBreak > rowStart {
+					pos = lastBreak
+				} else if pos == rowStart && pos < end {
+					// a rune wider than the row
+					pos++
+				}
+				rows = append(rows, textRow{line: line, start: rowStart, end: pos, first: first})
+				if pos == end && cursor == end && w >= width {
+					rows = append(rows, textRow{line: line, start: end, end: end})
+				}
+			}
+		}
+		if end == len(runes) {
+			return rows
+		}
+		start = end + 1
+		line++
+	}
+}
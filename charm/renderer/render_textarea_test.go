@@ -0,0 +1,87 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+func TestTextArea(t *testing.T) {
+	tests := []struct {
+		name     string
+		props    dom.TextAreaProps
+		expected string
+	}{
+		{
+			name:     "Lines",
+			props:    dom.TextAreaProps{Value: "one\ntwo"},
+			expected: "one       \ntwo       ",
+		},
+		{
+			name:     "SoftWrap",
+			props:    dom.TextAreaProps{Value: "hello big world"},
+			expected: "hello big \nworld     ",
+		},
+		{
+			name:     "WrapLongWord",
+			props:    dom.TextAreaProps{Value: "abcdefghijkl"},
+			expected: "abcdefghij\nkl        ",
+		},
+		{
+			name:     "WideCharacters",
+			props:    dom.TextAreaProps{Value: "你好世界你好"},
+			expected: "你好世界你\n好        ",
+		},
+		{
+			name:     "LineNumbers",
+			props:    dom.TextAreaProps{Value: "one\ntwo words", LineNumbers: true},
+			expected: "1 one     \n2 two     \n  words   ",
+		},
+		{
+			name:     "FollowsCursor",
+			props:    dom.TextAreaProps{Value: "1\n2\n3\n4", Height: 2, CursorPosition: 4},
+			expected: "2         \n3         ",
+		},
+		{
+			name:     "NoWrapFollowsCursor",
+			props:    dom.TextAreaProps{Value: "abcdefghijkl\nxy", NoWrap: true, CursorPosition: 12},
+			expected: "defghijkl \n          ",
+		},
+		{
+			name:     "Placeholder",
+			props:    dom.TextAreaProps{Placeholder: "Message"},
+			expected: "Message   ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rect := NewInteractiveCharmRenderer().RenderToRect(dom.TextArea(tt.props), 10, 5)
+			if got := StripColor(rect.String()); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTextAreaCursorAndSelection(t *testing.T) {
+	anchor := 1
+	props := dom.TextAreaProps{Value: "abcd\nef", CursorPosition: 3, SelectionAnchor: &anchor, Focused: true}
+	rect := NewInteractiveCharmRenderer().RenderToRect(dom.TextArea(props), 6, 3)
+
+	var reversed string
+	for _, c := range rect.Cells[0] {
+		if c.Style.Attrs&AttrReverse != 0 {
+			reversed += c.Content
+		}
+	}
+	if reversed != "bcd" {
+		t.Errorf("expected selection and cursor on %q, got %q", "bcd", reversed)
+	}
+
+	// the cursor at the end of a full row moves to a row of its own
+	props = dom.TextAreaProps{Value: "abcdef", CursorPosition: 6, Focused: true}
+	rect = NewInteractiveCharmRenderer().RenderToRect(dom.TextArea(props), 6, 3)
+	if rect.Height != 2 || rect.Cells[1][0].Style.Attrs&AttrReverse == 0 {
+		t.Errorf("expected the cursor at the start of a second row, got:\n%s", StripColor(rect.String()))
+	}
+}
@@ -1,9 +1,11 @@
 package renderer
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
 )
 
 // TestZDivBasicOverlay tests basic zdiv overlay functionality
@@ -360,3 +362,129 @@ func TestZDivComplexOverlay(t *testing.T) {
 		}
 	})
 }
+
+// TestZDivPositionedChildren tests children placed with offsets, anchors and centering
+func TestZDivPositionedChildren(t *testing.T) {
+	background := func() *dom.Node {
+		rows := make([]*dom.Node, 5)
+		for i := range rows {
+			rows[i] = dom.Text("..........")
+		}
+		return dom.Div(dom.DivProps{}, rows...)
+	}
+
+	tests := []struct {
+		name     string
+		position dom.Position
+		expected string
+	}{
+		{
+			name:     "TopLeftByDefault",
+			expected: "AB........\n..........\n..........\n..........\n..........",
+		},
+		{
+			name:     "TopLeftOffsets",
+			position: dom.Position{Top: styles.Int(1), Left: styles.Int(3)},
+			expected: "..........\n...AB.....\n..........\n..........\n..........",
+		},
+		{
+			name:     "BottomRightCorner",
+			position: dom.Position{Bottom: styles.Int(0), Right: styles.Int(0)},
+			expected: "..........\n..........\n..........\n..........\n........AB",
+		},
+		{
+			name:     "TopRightWithOffsets",
+			position: dom.Position{Top: styles.Int(1), Right: styles.Int(2)},
+			expected: "..........\n......AB..\n..........\n..........\n..........",
+		},
+		{
+			name:     "Centered",
+			position: dom.Position{CenterX: true, CenterY: true},
+			expected: "..........\n..........\n....AB....\n..........\n..........",
+		},
+		{
+			name:     "CenteredHorizontallyAtBottom",
+			position: dom.Position{CenterX: true, Bottom: styles.Int(1)},
+			expected: "..........\n..........\n..........\n....AB....\n..........",
+		},
+		{
+			name:     "ClippedAtEdge",
+			position: dom.Position{Left: styles.Int(9)},
+			expected: ".........A\n..........\n..........\n..........\n..........",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			child := dom.Div(dom.DivProps{Position: tt.position}, dom.Text("AB"))
+			zdiv := dom.ZDiv(dom.DivProps{}, background(), child)
+
+			rect := NewInteractiveCharmRenderer().RenderToRect(zdiv, 10, 5)
+			if got := StripColor(rect.String()); got != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, got)
+			}
+
+			// the layout places the child where it is drawn
+			bounds, ok := rect.Layout.BoundsOf(child)
+			if !ok {
+				t.Fatalf("expected child in layout")
+			}
+			row := strings.Split(tt.expected, "\n")[bounds.Y]
+			if !strings.HasPrefix(row[bounds.X:], "A") {
+				t.Errorf("expected child bounds at the A, got %+v", bounds)
+			}
+		})
+	}
+}
+
+// TestZDivPositionedChildGrowsToAvailableSpace tests that a centered child
+// makes the ZDiv as large as the space it is given
+func TestZDivPositionedChildGrowsToAvailableSpace(t *testing.T) {
+	zdiv := dom.ZDiv(dom.DivProps{},
+		dom.Text("list"),
+		dom.Div(dom.DivProps{Position: dom.Position{CenterX: true, CenterY: true}}, dom.Text("XX")),
+	)
+
+	rect := NewInteractiveCharmRenderer().RenderToRect(zdiv, 6, 3)
+
+	expected := "list  \n  XX  \n      "
+	if got := StripColor(rect.String()); got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+// TestZDivPositionsFocusScope tests that a dialog in a focus scope is
+// placed by the Position of the scope
+func TestZDivPositionsFocusScope(t *testing.T) {
+	zdiv := dom.ZDiv(dom.DivProps{},
+		dom.Text("list"),
+		dom.FocusScope(dom.FocusScopeProps{Position: dom.Position{CenterX: true, CenterY: true}}, dom.Text("XX")),
+	)
+
+	rect := NewInteractiveCharmRenderer().RenderToRect(zdiv, 6, 3)
+
+	expected := "list  \n  XX  \n      "
+	if got := StripColor(rect.String()); got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
+
+// TestZDivPositionsKeyHelp tests that a key help filled by the DOM is
+// placed by its Position, like a which-key popup
+func TestZDivPositionsKeyHelp(t *testing.T) {
+	zdiv := dom.KeyMap(dom.KeyMapProps{Bindings: []dom.Binding{
+		{Keys: "q", Description: "quit", Handler: func() {}},
+		{Keys: "?", Description: "help", Handler: func() {}},
+	}}, dom.ZDiv(dom.DivProps{},
+		dom.Text("list"),
+		dom.KeyHelp(dom.KeyHelpProps{Position: dom.Position{Right: styles.Int(0), Bottom: styles.Int(0)}}),
+	))
+	dom.NewDOM(zdiv, &dom.Window{})
+
+	rect := NewInteractiveCharmRenderer().RenderToRect(zdiv, 10, 3)
+
+	expected := "list      \n   q  quit\n   ?  help"
+	if got := StripColor(rect.String()); got != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, got)
+	}
+}
@@ -0,0 +1,64 @@
+package renderer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/charm/charmtest"
+	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+// TestSnapshots renders every kind of element against the golden files
+// in testdata/TestSnapshots, run with CHARMTEST_UPDATE=1 after intended changes
+func TestSnapshots(t *testing.T) {
+	anchor := 2
+	lines := make([]*dom.Node, 10)
+	for i := range lines {
+		lines[i] = dom.Text("line " + strings.Repeat("#", i))
+	}
+	tests := []struct {
+		name   string
+		node   *dom.Node
+		width  int
+		height int
+	}{
+		{"Text", dom.Div(dom.DivProps{},
+			dom.Text("plain"),
+			dom.Text("styled", styles.Style{Color: "#ff0000", Bold: true, Underline: true}),
+			dom.Text("wide 你好"),
+		), 20, 3},
+		{"Headings", dom.Div(dom.DivProps{},
+			dom.H1(dom.DivProps{}, dom.Text("Title")),
+			dom.H2(dom.DivProps{}, dom.Text("Subtitle")),
+			dom.P(dom.DivProps{}, dom.Text("A paragraph"), dom.Span(dom.DivProps{Style: styles.Style{Italic: true}}, dom.Text(" with a span"))),
+		), 30, 6},
+		{"Border", dom.Div(dom.DivProps{Style: styles.Style{BorderColor: "#00ff00", BorderRouned: true, PaddingLeft: styles.Int(1)}},
+			dom.Text("boxed"),
+		), 12, 4},
+		{"HDiv", dom.HDiv(dom.DivProps{Gap: 1},
+			dom.Text("left"), dom.Spacer(), dom.Text("right", styles.Style{BackgroundColor: "#0000ff"}),
+		), 20, 1},
+		{"ZDiv", dom.ZDiv(dom.DivProps{},
+			dom.Div(dom.DivProps{}, dom.Text("background"), dom.Text("background")),
+			dom.Div(dom.DivProps{Position: dom.Position{CenterX: true, CenterY: true}}, dom.Text("top", styles.Style{BackgroundColor: "#333333"})),
+		), 12, 3},
+		{"Button", dom.Button(dom.ButtonProps{}, dom.Text("OK")), 10, 1},
+		{"Input", dom.Input(dom.InputProps{Value: "hello", Focused: true, CursorPosition: 5, Width: 12}), 20, 3},
+		{"TextArea", dom.TextArea(dom.TextAreaProps{Value: "first\nsecond", Focused: true, CursorPosition: 8, SelectionAnchor: &anchor, Width: 10, Height: 3}), 10, 3},
+		{"List", dom.Ul(dom.DivProps{},
+			dom.Li(dom.ListItemProps{}, dom.Text("one")),
+			dom.Li(dom.ListItemProps{Selected: true}, dom.Text("two")),
+		), 10, 2},
+		{"ScrollView", dom.ScrollView(dom.ScrollViewProps{Height: 4, ScrollOffset: 3, Scrollbar: true}, lines...), 14, 4},
+		{"KeyHelp", dom.KeyMap(dom.KeyMapProps{Bindings: []dom.Binding{
+			{Keys: "ctrl+s", Description: "save", Handler: func() {}},
+			{Keys: "g g", Description: "top", Handler: func() {}},
+		}}, dom.KeyHelp(dom.KeyHelpProps{})), 14, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charmtest.Snapshot(t, tt.node, tt.width, tt.height)
+		})
+	}
+}
@@ -2,8 +2,9 @@ package renderer
 
 import (
 	"github.com/charmbracelet/lipgloss"
-	"github.com/xhd2015/go-dom-tui/colors"
+	"github.com/xhd2015/go-dom-tui/dom"
 	"github.com/xhd2015/go-dom-tui/styles"
+	"github.com/xhd2015/go-dom-tui/theme"
 )
 
 // CharmStyles holds various lipgloss styles for rendering
@@ -20,65 +21,56 @@ type CharmStyles struct {
 	CompactSuccess lipgloss.Style
 	Input          lipgloss.Style
 	Prompt         lipgloss.Style
+	Placeholder    lipgloss.Style
 	Success        lipgloss.Style
 	Error          lipgloss.Style
+
+	// Elements are the styles of the other element types the theme styles
+	Elements map[string]lipgloss.Style
+
+	theme theme.Theme // resolves the palette names in the Style props of nodes
 }
 
-func defaultStyles() CharmStyles {
-	return CharmStyles{
-		Title: lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
-			Padding(0, 0).
-			Margin(0, 0),
-		Subtitle: lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FFF7DB")).
-			Background(lipgloss.Color("#F25D94")).
-			Padding(0, 0).
-			Margin(0, 0),
-		Text: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262")).
-			Inline(true),
-		Button: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFF")).
-			Background(lipgloss.Color("#04B575")).
-			Bold(true),
-		Container: lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#874BFD")),
-		CompactDiv: lipgloss.NewStyle().
-			Padding(0, 0).
-			Margin(0, 0),
-		NoBorderDiv: lipgloss.NewStyle(),
-		CompactText: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262")).
-			Margin(0, 0),
-		CompactSuccess: lipgloss.NewStyle().
-			Foreground(lipgloss.Color(colors.GREEN_SUCCESS)).
-			Bold(true).
-			Margin(0, 0),
-		Input: lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder()).
-			Padding(0, 1).
-			Margin(0, 1).
-			Width(60),
-		InputText: lipgloss.NewStyle().
-			Margin(0, 1),
-		Prompt: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFF00")).
-			Bold(true).
-			Margin(0, 1),
-		Success: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FF00")).
-			Bold(true).
-			Margin(0, 1),
-		Error: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
-			Bold(true).
-			Margin(0, 1),
-	}
+// NewCharmStyles returns the styles of the elements in theme t,
+// on top of the spacing and borders elements are laid out with
+func NewCharmStyles(t theme.Theme) CharmStyles {
+	return newCharmStyles(lipgloss.DefaultRenderer(), t)
+}
+
+// newCharmStyles is NewCharmStyles with styles drawn by r
+func newCharmStyles(r *lipgloss.Renderer, t theme.Theme) CharmStyles {
+	apply := func(base lipgloss.Style, key string) lipgloss.Style {
+		return domStyleToCharmStyle(base, t.Element(key))
+	}
+	s := CharmStyles{
+		Title:          apply(r.NewStyle().Padding(0, 0).Margin(0, 0), dom.ElementTypeH1),
+		Subtitle:       apply(r.NewStyle().Padding(0, 0).Margin(0, 0), dom.ElementTypeH2),
+		Text:           apply(r.NewStyle().Inline(true), dom.ElementTypeText),
+		Button:         apply(r.NewStyle(), dom.ElementTypeButton),
+		Container:      apply(r.NewStyle().Border(lipgloss.RoundedBorder()), theme.Container),
+		CompactDiv:     r.NewStyle().Padding(0, 0).Margin(0, 0),
+		NoBorderDiv:    apply(r.NewStyle(), dom.ElementTypeDiv),
+		CompactText:    apply(r.NewStyle().Margin(0, 0), dom.ElementTypeLi),
+		CompactSuccess: apply(r.NewStyle().Margin(0, 0), theme.ListItemSelected),
+		Input:          apply(r.NewStyle().Border(lipgloss.NormalBorder()).Padding(0, 1).Margin(0, 1).Width(60), dom.ElementTypeInput),
+		InputText:      apply(r.NewStyle().Margin(0, 1), theme.InputText),
+		Prompt:         apply(r.NewStyle().Margin(0, 1), theme.InputPrompt),
+		Success:        apply(r.NewStyle().Margin(0, 1), theme.Success),
+		Error:          apply(r.NewStyle().Margin(0, 1), theme.Error),
+		Elements:       make(map[string]lipgloss.Style),
+		theme:          t,
+	}
+	s.Placeholder = apply(s.Text, theme.InputPlaceholder)
+	for key := range t.Elements {
+		switch key {
+		case dom.ElementTypeP:
+			s.Elements[key] = apply(s.Text, key)
+		case dom.ElementTypeSpan, dom.ElementTypeHDiv, dom.ElementTypeZDiv, dom.ElementTypeUl,
+			dom.ElementTypeTextArea, dom.ElementTypeScrollView:
+			s.Elements[key] = apply(s.NoBorderDiv, key)
+		}
+	}
+	return s
 }
 
 // dom style to charm style
@@ -0,0 +1,84 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+const ellipsis = "…"
+
+// textOverflowOf returns the TextOverflow prop of a text-bearing element
+func textOverflowOf(vnode *dom.Node) dom.TextOverflow {
+	if vnode.Props == nil {
+		return ""
+	}
+	if v, ok := vnode.Props.Get("textOverflow"); ok {
+		if mode, ok := v.(dom.TextOverflow); ok {
+			return mode
+		}
+	}
+	return ""
+}
+
+// renderTextInWidth renders text with style in width cells, laying out
+// the lines of text inside the margin, border and padding of style
+func renderTextInWidth(style lipgloss.Style, text string, width int, mode dom.TextOverflow) Rectangle {
+	if style.GetInline() {
+		// inline styles join the lines of text and draw no insets,
+		// lay out what they render
+		return NewRectangle(fitText(style.Render(text), width, mode))
+	}
+	margin, border, padding := styleInsets(style)
+	inner := width - margin.Left - margin.Right - border.Left - border.Right - padding.Left - padding.Right
+	return NewRectangle(style.Render(fitText(text, inner, mode)))
+}
+
+// fitText lays out each line of text in width cells the way mode says.
+// Widths are measured in terminal cells, so wide characters and emoji
+// count as two, and styles of the text are kept.
+func fitText(text string, width int, mode dom.TextOverflow) string {
+	if width <= 0 {
+		return ""
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if ansi.StringWidth(line) <= width {
+			continue
+		}
+		lines[i] = fitLine(line, width, mode)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fitLine lays out a line wider than width
+func fitLine(line string, width int, mode dom.TextOverflow) string {
+	lineWidth := ansi.StringWidth(line)
+	switch mode {
+	case dom.TextWrapHard:
+		return ansi.Hardwrap(line, width, true)
+	case dom.TextEllipsis:
+		return ansi.Truncate(line, width, ellipsis)
+	case dom.TextEllipsisStart:
+		return ellipsis + lineEnd(line, lineWidth, width-1)
+	case dom.TextEllipsisMiddle:
+		head := width / 2
+		return ansi.Truncate(line, head, "") + ellipsis + lineEnd(line, lineWidth, width-1-head)
+	case dom.TextWrap:
+		return ansi.Wrap(line, width, "")
+	}
+	return ansi.Truncate(line, width, "")
+}
+
+// lineEnd returns the end of line that fits in width cells, without
+// the half of a wide character cut at its start
+func lineEnd(line string, lineWidth, width int) string {
+	for n := lineWidth - width; n < lineWidth; n++ {
+		if end := ansi.TruncateLeft(line, n, ""); ansi.StringWidth(end) <= width {
+			return end
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+func TestFitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		mode     dom.TextOverflow
+		expected string
+	}{
+		{"Fits", "hello", 5, dom.TextEllipsis, "hello"},
+		{"ClipByDefault", "hello big world", 9, "", "hello big"},
+		{"WrapWords", "hello big world", 9, dom.TextWrap, "hello big\nworld"},
+		{"WrapLongWord", "abcdefghij", 4, dom.TextWrap, "abcd\nefgh\nij"},
+		{"WrapKeepsLines", "one two\nsix", 4, dom.TextWrap, "one\ntwo\nsix"},
+		{"WrapHard", "hello world", 4, dom.TextWrapHard, "hell\no wo\nrld"},
+		{"Ellipsis", "hello world", 8, dom.TextEllipsis, "hello w…"},
+		{"EllipsisStart", "hello world", 8, dom.TextEllipsisStart, "…o world"},
+		{"EllipsisMiddle", "hello world", 8, dom.TextEllipsisMiddle, "hell…rld"},
+		{"Clip", "hello world", 8, dom.TextClip, "hello wo"},
+		{"EllipsisPerLine", "abcdef\nab", 4, dom.TextEllipsis, "abc…\nab"},
+		{"WideWrap", "你好世界", 5, dom.TextWrap, "你好\n世界"},
+		{"WideEllipsis", "你好世界", 6, dom.TextEllipsis, "你好…"},
+		{"WideEllipsisStart", "你好世界", 6, dom.TextEllipsisStart, "…世界"},
+		{"WideClip", "你好世界", 5, dom.TextClip, "你好"},
+		{"Emoji", "🙂🙂🙂", 5, dom.TextEllipsis, "🙂🙂…"},
+		{"NoWidth", "hello", 0, dom.TextWrap, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fitText(tt.text, tt.width, tt.mode)
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+			if tt.width > 0 {
+				for _, line := range splitLines(got) {
+					if w := ansi.StringWidth(line); w > tt.width {
+						t.Errorf("line %q is %d cells wide, over %d", line, w, tt.width)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFitTextKeepsStyles(t *testing.T) {
+	got := fitText("\x1b[31mhello world\x1b[0m", 6, dom.TextEllipsis)
+	if expected := "hello…"; ansi.Strip(got) != expected {
+		t.Errorf("Expected %q, got %q", expected, ansi.Strip(got))
+	}
+	if got[:5] != "\x1b[31m" {
+		t.Errorf("expected the color to be kept, got %q", got)
+	}
+}
+
+// TestTextRespectsWidth tests that text in an HDiv no longer
+// pushes its siblings past the width of the terminal
+func TestTextRespectsWidth(t *testing.T) {
+	hdiv := dom.HDiv(dom.DivProps{},
+		dom.Text("label "),
+		dom.TextWithProps("a rather long description", dom.TextNodeProps{TextOverflow: dom.TextEllipsis}),
+	)
+	rect := NewInteractiveCharmRenderer().RenderToRect(hdiv, 16, 3)
+	if expected := "label a rather …"; StripColor(rect.String()) != expected {
+		t.Errorf("Expected %q, got %q", expected, StripColor(rect.String()))
+	}
+
+	p := dom.P(dom.DivProps{}, dom.Text("wrapped by default"))
+	rect = NewInteractiveCharmRenderer().RenderToRect(p, 10, 5)
+	if rect.Width > 10 {
+		t.Errorf("expected p within 10 cells, got %d:\n%s", rect.Width, StripColor(rect.String()))
+	}
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			lines = append(lines, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(lines, s[start:])
+}
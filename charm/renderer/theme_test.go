@@ -0,0 +1,42 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
+	"github.com/xhd2015/go-dom-tui/theme"
+)
+
+func TestSetTheme(t *testing.T) {
+	tree := func(errorColor string) *dom.Node {
+		root := dom.Div(dom.DivProps{},
+			dom.H1(dom.DivProps{}, dom.Text("Title")),
+			dom.Text("failed", styles.Style{Color: errorColor}),
+		)
+		dom.NewDOM(root, &dom.Window{Width: 10, Height: 2})
+		return root
+	}
+	// a palette name in the Style prop of a node renders like its color
+	root := tree("error")
+	cr := trueColorRenderer()
+	dark := cr.RenderToRect(root, 10, 2)
+	if want := trueColorRenderer().RenderToRect(tree(theme.Dark().Color("error")), 10, 2); dark.String() != want.String() {
+		t.Errorf("palette names render differently from their colors:\n%q\nvs\n%q", dark.String(), want.String())
+	}
+
+	// the same tree, so that a cached render would show the previous theme
+	cr.SetTheme(theme.Light())
+	light := cr.RenderToRect(root, 10, 2)
+	fresh := trueColorRenderer()
+	fresh.SetTheme(theme.Light())
+	if want := fresh.RenderToRect(tree(theme.Light().Color("error")), 10, 2); light.String() != want.String() {
+		t.Errorf("render after SetTheme differs from a fresh render:\n%q\nvs\n%q", light.String(), want.String())
+	}
+	if dark.Cells[0][0].Style.Bg == light.Cells[0][0].Style.Bg {
+		t.Errorf("h1 background did not change with the theme: %+v", light.Cells[0][0].Style.Bg)
+	}
+	if dark.Cells[1][0].Style.Fg == light.Cells[1][0].Style.Fg {
+		t.Errorf("error color did not change with the theme: %+v", light.Cells[1][0].Style.Fg)
+	}
+}
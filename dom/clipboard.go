@@ -0,0 +1,97 @@
+package dom
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Clipboard is where text fields copy (Ctrl-C) and cut (Ctrl-X) the
+// selection to, and paste (Ctrl-V) from
+type Clipboard interface {
+	ReadText() (string, error)
+	WriteText(text string) error
+}
+
+// MemoryClipboard keeps the text in memory. It is the clipboard of a DOM
+// without one, which keeps copy and paste working inside the app and in tests.
+type MemoryClipboard struct {
+	mu   sync.Mutex
+	text string
+}
+
+func (c *MemoryClipboard) ReadText() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.text, nil
+}
+
+func (c *MemoryClipboard) WriteText(text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.text = text
+	return nil
+}
+
+// OSC52Clipboard copies to the clipboard of the terminal with the OSC 52
+// escape sequence, which also works over SSH. Terminals rarely let apps
+// read their clipboard, so pasting returns the text copied last; text
+// pasted from the terminal arrives as a bracketed paste instead.
+type OSC52Clipboard struct {
+	Out io.Writer // Usually the terminal, os.Stdout
+
+	memory MemoryClipboard
+}
+
+// NewOSC52Clipboard creates a clipboard writing to out
+func NewOSC52Clipboard(out io.Writer) *OSC52Clipboard {
+	return &OSC52Clipboard{Out: out}
+}
+
+func (c *OSC52Clipboard) ReadText() (string, error) {
+	return c.memory.ReadText()
+}
+
+func (c *OSC52Clipboard) WriteText(text string) error {
+	c.memory.WriteText(text)
+	_, err := fmt.Fprintf(c.Out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
+
+// clipboard returns the clipboard of the DOM, a MemoryClipboard if none is set
+func (d *DOM) clipboard() Clipboard {
+	if d.Clipboard == nil {
+		d.Clipboard = &MemoryClipboard{}
+	}
+	return d.Clipboard
+}
+
+// clipboardEdit applies copy (Ctrl-C), cut (Ctrl-X) and paste (Ctrl-V) to
+// a text field. Copy and cut need a selection, paste replaces it.
+// ok is false if key is none of them, or if the clipboard failed.
+func (d *DOM) clipboardEdit(t TextEdit, e *KeydownEvent, maxLength int) (result TextEdit, ok bool) {
+	runes := []rune(t.Value)
+	t = t.clamped(len(runes))
+	start, end, selected := t.Selection()
+	switch e.KeyType {
+	case KeyTypeCtrlC, KeyTypeCtrlX:
+		if !selected {
+			return t, false
+		}
+		if err := d.clipboard().WriteText(string(runes[start:end])); err != nil {
+			return t, false
+		}
+		if e.KeyType == KeyTypeCtrlC {
+			return t, true
+		}
+		return replaceText(runes, start, end, nil, maxLength), true
+	case KeyTypeCtrlV:
+		text, err := d.clipboard().ReadText()
+		if err != nil || text == "" {
+			return t, false
+		}
+		return replaceText(runes, start, end, []rune(text), maxLength), true
+	}
+	return t, false
+}
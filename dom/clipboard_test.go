@@ -0,0 +1,124 @@
+package dom
+
+import (
+	"bytes"
+	"testing"
+)
+
+// inputApp keeps the state of an input like an app would
+type inputApp struct {
+	value  string
+	cursor int
+	anchor *int
+}
+
+func (a *inputApp) press(d *DOM, events ...*KeydownEvent) {
+	for _, e := range events {
+		d.Root = Input(InputProps{
+			Value:           a.value,
+			CursorPosition:  a.cursor,
+			SelectionAnchor: a.anchor,
+			Focused:         true,
+			OnChange:        func(s string) { a.value = s },
+			OnCursorMove:    func(p int) { a.cursor = p },
+			OnSelect:        func(p *int) { a.anchor = p },
+		})
+		d.DispatchKeyDownEvent(e)
+	}
+}
+
+func (a *inputApp) selection() string {
+	t := TextEdit{Value: a.value, Cursor: a.cursor, Anchor: a.anchor}
+	start, end, _ := t.Selection()
+	return string([]rune(a.value)[start:end])
+}
+
+func TestInputSelection(t *testing.T) {
+	d := NewDOM(nil, &Window{})
+	app := &inputApp{value: "hello world", cursor: 6}
+
+	app.press(d, &KeydownEvent{KeyType: KeyTypeShiftEnd})
+	if got := app.selection(); got != "world" {
+		t.Fatalf("expected shift+end to select %q, got %q", "world", got)
+	}
+	app.press(d, &KeydownEvent{KeyType: KeyTypeShiftLeft}, &KeydownEvent{KeyType: KeyTypeShiftLeft})
+	if got := app.selection(); got != "wor" {
+		t.Fatalf("expected shift+left to shrink the selection to %q, got %q", "wor", got)
+	}
+	app.press(d, &KeydownEvent{Runes: []rune("W")})
+	if app.value != "hello Wld" || app.anchor != nil || app.cursor != 7 {
+		t.Fatalf("expected typing to replace the selection, got %q at %d", app.value, app.cursor)
+	}
+	app.press(d, &KeydownEvent{Runes: []rune("a"), Alt: true})
+	if got := app.selection(); got != "hello Wld" {
+		t.Fatalf("expected alt+a to select all, got %q", got)
+	}
+	app.press(d, &KeydownEvent{KeyType: KeyTypeRight})
+	if app.anchor != nil || app.cursor != 9 {
+		t.Errorf("expected right to collapse the selection at its end, got cursor %d anchor %v", app.cursor, app.anchor)
+	}
+}
+
+func TestInputClipboard(t *testing.T) {
+	clipboard := &MemoryClipboard{}
+	d := NewDOM(nil, &Window{})
+	d.Clipboard = clipboard
+	start := 0
+	app := &inputApp{value: "copy me", cursor: 4, anchor: &start}
+
+	app.press(d, &KeydownEvent{KeyType: KeyTypeCtrlC})
+	if text, _ := clipboard.ReadText(); text != "copy" || app.value != "copy me" {
+		t.Fatalf("expected %q copied and the value kept, got %q and %q", "copy", text, app.value)
+	}
+	app.press(d, &KeydownEvent{KeyType: KeyTypeEnd}, &KeydownEvent{KeyType: KeyTypeCtrlV})
+	if app.value != "copy mecopy" || app.cursor != 11 {
+		t.Fatalf("expected paste at the cursor, got %q at %d", app.value, app.cursor)
+	}
+	app.press(d, &KeydownEvent{KeyType: KeyTypeShiftHome}, &KeydownEvent{KeyType: KeyTypeCtrlX})
+	if text, _ := clipboard.ReadText(); text != "copy mecopy" || app.value != "" {
+		t.Fatalf("expected everything cut, got %q left and %q copied", app.value, text)
+	}
+
+	// bracketed paste replaces the selection too
+	app.value, app.cursor, app.anchor = "abc", 2, &start
+	app.press(d, &KeydownEvent{Runes: []rune("xy"), Paste: true})
+	if app.value != "xyc" || app.cursor != 2 {
+		t.Errorf("expected paste to replace the selection, got %q at %d", app.value, app.cursor)
+	}
+}
+
+func TestClipboardStaleAnchor(t *testing.T) {
+	d := NewDOM(nil, &Window{})
+	stale := 10
+	// the value was cleared on submit, without resetting the selection
+	app := &inputApp{value: "abc", cursor: 1, anchor: &stale}
+
+	app.press(d, &KeydownEvent{KeyType: KeyTypeCtrlX})
+	if text, _ := d.clipboard().ReadText(); text != "bc" || app.value != "a" {
+		t.Errorf("expected the selection cut up to the end, got %q left and %q copied", app.value, text)
+	}
+}
+
+func TestClipboardSurvivesRerender(t *testing.T) {
+	prev := NewDOM(nil, &Window{})
+	prev.clipboard().WriteText("kept")
+	d := NewDOM(nil, &Window{})
+	d.Inherit(prev)
+	if text, _ := d.clipboard().ReadText(); text != "kept" {
+		t.Errorf("expected the clipboard carried over, got %q", text)
+	}
+}
+
+func TestOSC52Clipboard(t *testing.T) {
+	var out bytes.Buffer
+	clipboard := NewOSC52Clipboard(&out)
+	if err := clipboard.WriteText("hi"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "\x1b]52;c;aGk=\a"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+	if text, _ := clipboard.ReadText(); text != "hi" {
+		t.Errorf("expected the text copied last, got %q", text)
+	}
+}
@@ -1,6 +1,8 @@
 package dom
 
 import (
+	"strconv"
+
 	"github.com/xhd2015/go-dom-tui/log"
 )
 
@@ -49,15 +51,20 @@ type Node struct {
 
 	Text string // Text content (for text nodes)
 
+	Memo []interface{} // what the subtree depends on, see Memo
+
 	// Component-specific fields
 	Component      Component
-	State          interface{} // Component state
+	State          interface{} // Component state, the runtime instance once expanded
 	Hooks          []Hook      // Component hooks
 	EffectCleanups []func()    // Effect cleanup functions
 
 	// DOM extension fields
 	Parent *Node   // Parent node for event bubbling
 	Window *Window // Reference to global window state
+
+	path    string // position of the node in the tree, set up by NewDOM
+	focused bool   // the node has the focus the DOM owns, see DOM.OwnFocus
 }
 
 // Component represents a React-like component function
@@ -85,6 +92,39 @@ type DOM struct {
 	FocusedNode        *Node
 	PreviousFocuseable *Node
 	NextFocuseable     *Node
+
+	// HitTester resolves screen coordinates to nodes for mouse events,
+	// usually the layout produced by the last render
+	HitTester HitTester
+
+	// Clipboard is what text fields copy to and paste from,
+	// a MemoryClipboard when nil
+	Clipboard Clipboard
+
+	// Listeners are the handlers of the whole DOM, see AddEventListener
+	Listeners *EventListeners
+
+	// OwnFocus makes the DOM keep the focus across renders, see FocusedKey,
+	// so apps do not have to keep it in their state and set Focused props.
+	// A node with Focused set still takes the focus. Inherit carries it over.
+	OwnFocus    bool
+	focusedPath string
+
+	mouse mouseState
+
+	// focus scopes in document order, and for each the path of the
+	// node to focus again when it unmounts, see UpdateFocusScopes
+	focusScopes  []*Node
+	focusReturns map[string]string
+
+	// the start of a key sequence pressed so far, and the KeyHelp nodes
+	// listing the bindings, see runKeymap
+	keys     keySequence
+	keyHelps []*Node
+
+	// the geometry of the scroll views by path, the last time the layout
+	// measured them, to clamp scrolling when it does not, see scrollExtent
+	scrollExtents map[string]ScrollExtent
 }
 
 // NewDOM creates a new DOM from a VNode tree
@@ -94,51 +134,144 @@ func NewDOM(root *Node, window *Window) *DOM {
 		Window: window,
 	}
 
-	dom.setupVNode(root, nil, window)
+	dom.setupVNode(root, nil, window, "")
+	dom.trackFocus()
+	dom.updateKeyHelp()
 
 	return dom
 }
 
+// Inherit carries interaction state that must survive re-renders
+// (pressed and hovered nodes, the clipboard, the listeners, where focus
+// scopes return the focus, the key sequence pressed so far, the focused
+// node when the DOM owns the focus) over from the previous DOM
+func (d *DOM) Inherit(prev *DOM) {
+	if prev == nil {
+		return
+	}
+	defer d.updateKeyHelp()
+	d.mouse = prev.mouse
+	d.keys = prev.keys
+	d.focusReturns = prev.focusReturns
+	d.scrollExtents = prev.scrollExtents
+	if d.Clipboard == nil {
+		d.Clipboard = prev.Clipboard
+	}
+	if d.Listeners == nil {
+		d.Listeners = prev.Listeners
+	}
+	if prev.OwnFocus {
+		d.OwnFocus = true
+	}
+	if !d.OwnFocus {
+		return
+	}
+	// a Focused prop overrides the focus carried over
+	if d.FocusedNode == nil && prev.focusedPath != "" {
+		if node := d.Root.findByPath(prev.focusedPath); node != nil && node.IsFocusable() {
+			d.takeFocus(node)
+		}
+	}
+	if d.FocusedNode != nil {
+		d.focusedPath = d.FocusedNode.path
+	}
+}
+
 // setupVNode recursively sets up VNodes with DOM functionality
-func (d *DOM) setupVNode(vnode *Node, parent *Node, window *Window) {
+func (d *DOM) setupVNode(vnode *Node, parent *Node, window *Window, path string) {
 	if vnode == nil {
 		return
 	}
 
-	focusable := vnode.IsFocusable()
-	focused := vnode.IsFocused()
-
 	vnode.Parent = parent
 	vnode.Window = window // Set window reference on all nodes
-
-	// Track first focusable node
-	if focusable && d.FirstFocusable == nil {
-		d.FirstFocusable = vnode
+	vnode.path = path
+	vnode.focused = false
+	switch vnode.Type {
+	case ElementTypeFocusScope:
+		d.focusScopes = append(d.focusScopes, vnode)
+	case ElementTypeKeyHelp:
+		d.keyHelps = append(d.keyHelps, vnode)
 	}
 
-	// Track focused node and set previous focusable
-	if focused && d.FocusedNode == nil {
-		d.FocusedNode = vnode
-		d.PreviousFocuseable = d.LastFocusable // Previous is the last focusable we've seen
+	// Process children in depth-first order
+	for i, child := range vnode.Children {
+		if child == nil {
+			continue
+		}
+		d.setupVNode(child, vnode, window, ChildPath(path, i, child))
 	}
+}
 
-	// If we have a focused node but no next focusable yet, and this is focusable
-	if d.FocusedNode != nil && d.NextFocuseable == nil && focusable && vnode != d.FocusedNode {
-		d.NextFocuseable = vnode
-	}
+// trackFocus finds the focused node and the focusable nodes around it
+// in document order, which the arrows move the focus to
+func (d *DOM) trackFocus() {
+	d.trackFocusBy((*Node).IsFocused)
+}
 
-	// Update last focusable before processing children
-	if focusable {
-		d.LastFocusable = vnode
-	}
+// trackFocusBy is trackFocus with the focused node the first one isFocused
+// reports, in document order
+func (d *DOM) trackFocusBy(isFocused func(*Node) bool) {
+	d.FirstFocusable, d.LastFocusable = nil, nil
+	d.FocusedNode, d.PreviousFocuseable, d.NextFocuseable = nil, nil, nil
+	var walk func(vnode *Node)
+	walk = func(vnode *Node) {
+		if vnode == nil {
+			return
+		}
+		focusable := vnode.IsFocusable()
+		if index, _ := vnode.GetTabIndex(); index < 0 {
+			// only focused programmatically, arrows skip it like Tab does
+			focusable = false
+		}
+		focused := isFocused(vnode)
 
-	// Process children in depth-first order
-	for _, child := range vnode.Children {
-		if child == nil {
-			continue
+		// Track first focusable node
+		if focusable && d.FirstFocusable == nil {
+			d.FirstFocusable = vnode
+		}
+
+		// Track focused node and set previous focusable
+		if focused && d.FocusedNode == nil {
+			d.FocusedNode = vnode
+			d.PreviousFocuseable = d.LastFocusable // Previous is the last focusable we've seen
+		}
+
+		// If we have a focused node but no next focusable yet, and this is focusable
+		if d.FocusedNode != nil && d.NextFocuseable == nil && focusable && vnode != d.FocusedNode {
+			d.NextFocuseable = vnode
+		}
+
+		// Update last focusable before processing children
+		if focusable {
+			d.LastFocusable = vnode
+		}
+
+		for _, child := range vnode.Children {
+			walk(child)
 		}
-		d.setupVNode(child, vnode, window)
 	}
+	walk(d.Root)
+	if d.OwnFocus {
+		d.focusedPath = ""
+		if d.FocusedNode != nil {
+			d.focusedPath = d.FocusedNode.path
+		}
+	}
+}
+
+// ChildPath builds the path of a child, preferring its key over its index
+func ChildPath(parentPath string, index int, child *Node) string {
+	if child.Key != "" {
+		return parentPath + "/" + child.Type + "#" + child.Key
+	}
+	return parentPath + "/" + child.Type + ":" + strconv.Itoa(index)
+}
+
+// Path returns the position of the node in the tree, as set up by NewDOM.
+// Paths are stable across re-renders as long as the tree shape is stable.
+func (c *Node) Path() string {
+	return c.path
 }
 
 // DispatchWindowEvent dispatches window-level events (like resize) to the DOM tree
@@ -156,6 +289,8 @@ func (d *DOM) DispatchWindowEvent(eventType EventType, windowEvent *WindowResize
 
 	// Handle at root level and propagate to interested components
 	d.handleWindowEventPropagation(d.Root, event)
+	event.CurrentTarget = nil
+	d.Listeners.run(event, false)
 }
 
 // handleWindowEventPropagation propagates window events through the DOM tree
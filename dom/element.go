@@ -14,8 +14,51 @@ func CreateNode(typ string, props Props, children ...*Node) *Node {
 	}
 }
 
+// CreateComponent creates a component node. The component is not called here
+// but by React.Render, on every render pass, where it gets its own instance
+// (hooks, effects) identified by its position in the tree and its "key" prop.
+// children are available to the component through React.UseChildren.
 func CreateComponent(component Component, props Props, children ...*Node) *Node {
-	panic("TODO: CreateComponent")
+	if props == nil {
+		props = NewStructProps(EmptyProps{})
+	}
+	return &Node{
+		Type:      ElementTypeComponent,
+		Props:     props,
+		Children:  children,
+		Key:       GetStringProp(props, "key"),
+		Component: component,
+	}
+}
+
+// Memo marks node as rendered only from deps: as long as they are equal,
+// compared with ==, and the node is given the same space, the renderer
+// reuses the output of its subtree from the last frame instead of rendering
+// it again. The subtree is still rendered again when the focus the DOM owns
+// moves into, out of or inside it. Memo takes effect below the root of trees
+// set up with NewDOM, whose paths identify the node across frames.
+func Memo(node *Node, deps ...interface{}) *Node {
+	if node == nil {
+		return nil
+	}
+	if deps == nil {
+		deps = []interface{}{}
+	}
+	node.Memo = deps
+	return node
+}
+
+// RenderComponent returns the output of a component node expanded by
+// React.Render, which it holds as its only child. It panics if the node was
+// not expanded: the component would have no instance for its hooks.
+func (c *Node) RenderComponent() *Node {
+	if c.State == nil {
+		panic("dom: component node rendered without React.Render, which expands component nodes")
+	}
+	if len(c.Children) == 0 {
+		return nil
+	}
+	return c.Children[0]
 }
 
 // Text creates a text node
@@ -57,6 +100,12 @@ func ZDiv(props DivProps, children ...*Node) *Node {
 	return CreateNode(ElementTypeZDiv, NewStructProps(props), children...)
 }
 
+// ScrollView creates a scrollable viewport that shows its children,
+// stacked vertically, from the line at props.ScrollOffset
+func ScrollView(props ScrollViewProps, children ...*Node) *Node {
+	return CreateNode(ElementTypeScrollView, NewStructProps(props), children...)
+}
+
 // Span creates a span component
 func Span(props DivProps, children ...*Node) *Node {
 	return CreateNode(ElementTypeSpan, NewStructProps(props), children...)
@@ -83,6 +132,11 @@ func Input(props InputProps, children ...*Node) *Node {
 	return CreateNode(ElementTypeInput, NewStructProps(props), children...)
 }
 
+// TextArea creates a multi-line text field, edited by EditText
+func TextArea(props TextAreaProps) *Node {
+	return CreateNode(ElementTypeTextArea, NewStructProps(props))
+}
+
 func Button(props ButtonProps, children ...*Node) *Node {
 	return CreateNode(ElementTypeButton, NewStructProps(props), children...)
 }
@@ -95,6 +149,22 @@ func Li(props ListItemProps, children ...*Node) *Node {
 	return CreateNode(ElementTypeLi, NewStructProps(props), children...)
 }
 
+// FocusScope confines Tab, Shift-Tab and arrow focus navigation to its
+// children, for dialogs and popup menus, see FocusScopeProps
+func FocusScope(props FocusScopeProps, children ...*Node) *Node {
+	return CreateNode(ElementTypeFocusScope, NewStructProps(props), children...)
+}
+
+// KeyMap binds keys and key sequences for its children, see KeyMapProps
+func KeyMap(props KeyMapProps, children ...*Node) *Node {
+	return CreateNode(ElementTypeKeyMap, NewStructProps(props), children...)
+}
+
+// KeyHelp lists the bindings active for the focused node, see KeyHelpProps
+func KeyHelp(props KeyHelpProps) *Node {
+	return CreateNode(ElementTypeKeyHelp, NewStructProps(props))
+}
+
 func Fragment(children ...*Node) *Node {
 	return CreateNode(ElementTypeFragment, NewStructProps(EmptyProps{}), children...)
 }
@@ -12,28 +12,66 @@ const (
 	// onKeydown
 	EventTypeKeydown EventType = "keydown"
 	EventTypeResize  EventType = "resize"
+
+	// mouse events, see MouseEvent
+	EventTypeMouseDown  EventType = "mousedown"
+	EventTypeMouseUp    EventType = "mouseup"
+	EventTypeClick      EventType = "click"
+	EventTypeWheel      EventType = "wheel"
+	EventTypeMouseMove  EventType = "mousemove"
+	EventTypeMouseEnter EventType = "mouseenter" // does not bubble
+	EventTypeMouseLeave EventType = "mouseleave" // does not bubble
 )
 
+// KeyType is the canonical form of a named key with its modifiers, see
+// Key.String. Any key can be compared against, not only the constants:
+// KeyType("ctrl+shift+left"), KeyType("alt+f4").
 type KeyType string
 
 const (
-	KeyTypeEnter     KeyType = "enter"
-	KeyTypeBackspace KeyType = "backspace"
-	KeyTypeDelete    KeyType = "delete"
-	KeyTypeTab       KeyType = "tab"
-	KeyTypeEsc       KeyType = "esc"
-	KeyTypeSpace     KeyType = "space"
-	KeyTypeUp        KeyType = "up"
-	KeyTypeDown      KeyType = "down"
-	KeyTypeLeft      KeyType = "left"
-	KeyTypeRight     KeyType = "right"
-	KeyTypeCtrlC     KeyType = "ctrl+c"
-	KeyTypeCtrlV     KeyType = "ctrl+v"
-	KeyTypeCtrlX     KeyType = "ctrl+x"
-	KeyTypeCtrlW     KeyType = "ctrl+w"
-	KeyTypeCtrlA     KeyType = "ctrl+a"
-	KeyTypeCtrlE     KeyType = "ctrl+e"
-	KeyTypeCtrlK     KeyType = "ctrl+k"
+	KeyTypeEnter      KeyType = "enter"
+	KeyTypeBackspace  KeyType = "backspace"
+	KeyTypeDelete     KeyType = "delete"
+	KeyTypeTab        KeyType = "tab"
+	KeyTypeShiftTab   KeyType = "shift+tab"
+	KeyTypeInsert     KeyType = "insert"
+	KeyTypeEsc        KeyType = "esc"
+	KeyTypeSpace      KeyType = "space"
+	KeyTypeUp         KeyType = "up"
+	KeyTypeDown       KeyType = "down"
+	KeyTypeLeft       KeyType = "left"
+	KeyTypeRight      KeyType = "right"
+	KeyTypePgUp       KeyType = "pgup"
+	KeyTypePgDown     KeyType = "pgdown"
+	KeyTypeHome       KeyType = "home"
+	KeyTypeEnd        KeyType = "end"
+	KeyTypeShiftUp    KeyType = "shift+up"
+	KeyTypeShiftDown  KeyType = "shift+down"
+	KeyTypeShiftLeft  KeyType = "shift+left"
+	KeyTypeShiftRight KeyType = "shift+right"
+	KeyTypeShiftHome  KeyType = "shift+home"
+	KeyTypeShiftEnd   KeyType = "shift+end"
+	KeyTypeCtrlC      KeyType = "ctrl+c"
+	KeyTypeCtrlV      KeyType = "ctrl+v"
+	KeyTypeCtrlX      KeyType = "ctrl+x"
+	KeyTypeCtrlW      KeyType = "ctrl+w"
+	KeyTypeCtrlA      KeyType = "ctrl+a"
+	KeyTypeCtrlE      KeyType = "ctrl+e"
+	KeyTypeCtrlK      KeyType = "ctrl+k"
+	KeyTypeCtrlY      KeyType = "ctrl+y"
+	KeyTypeCtrlZ      KeyType = "ctrl+z"
+	KeyTypeF1         KeyType = "f1"
+	KeyTypeF2         KeyType = "f2"
+	KeyTypeF3         KeyType = "f3"
+	KeyTypeF4         KeyType = "f4"
+	KeyTypeF5         KeyType = "f5"
+	KeyTypeF6         KeyType = "f6"
+	KeyTypeF7         KeyType = "f7"
+	KeyTypeF8         KeyType = "f8"
+	KeyTypeF9         KeyType = "f9"
+	KeyTypeF10        KeyType = "f10"
+	KeyTypeF11        KeyType = "f11"
+	KeyTypeF12        KeyType = "f12"
 )
 
 // EventHandler represents a DOM event handler function
@@ -45,20 +83,36 @@ type WindowResizeEvent struct {
 	Height int
 }
 
+// EventPhase is the stage of its dispatch an event is in
+type EventPhase int
+
+const (
+	PhaseNone      EventPhase = iota
+	PhaseCapturing            // capture listeners of the DOM, then from the root down to the parent of the target
+	PhaseAtTarget
+	PhaseBubbling // from the parent of the target up to the root, then listeners of the DOM
+)
+
 // DOMEvent represents a DOM-like event
 type DOMEvent struct {
 	Type          EventType
 	Target        *Node
-	CurrentTarget *Node
+	CurrentTarget *Node // nil for the listeners of the DOM
 	KeydownEvent  *KeydownEvent
 
-	DefaultPrevented   bool
-	PropagationStopped bool
-	BubblePhase        bool
-	WindowEvent        *WindowResizeEvent // For window-specific events
+	Phase                       EventPhase
+	DefaultPrevented            bool
+	PropagationStopped          bool
+	ImmediatePropagationStopped bool
+	BubblePhase                 bool               // Phase is PhaseBubbling
+	WindowEvent                 *WindowResizeEvent // For window-specific events
+	MouseEvent                  *MouseEvent        // For mouse events
 }
 
+// KeydownEvent is a key press. Named keys set KeyType, text typed or
+// pasted sets Runes instead, with Alt if it was held.
 type KeydownEvent struct {
+	Key     Key // The key and its modifiers, see GetKey for events created without it
 	KeyType KeyType
 	Runes   []rune
 	Alt     bool
@@ -70,21 +124,31 @@ func (e *DOMEvent) PreventDefault() {
 	e.DefaultPrevented = true
 }
 
-// StopPropagation stops the event from bubbling up
+// StopPropagation stops the event from reaching the next node,
+// the handlers of the current node still run
 func (e *DOMEvent) StopPropagation() {
 	e.PropagationStopped = true
 }
 
-// DispatchEvent dispatches an event to the focused node and bubbles it up
+// StopImmediatePropagation stops the event from reaching any other
+// handler, including the other handlers of the current node
+func (e *DOMEvent) StopImmediatePropagation() {
+	e.PropagationStopped = true
+	e.ImmediatePropagationStopped = true
+}
+
+// DispatchEvent dispatches an event to the focused node and bubbles it up.
+// The focused node is the one the DOM moved the focus to last, even if the
+// app did not render it focused yet.
 func (d *DOM) DispatchKeyDownEvent(keyEvent *KeydownEvent) {
-	eventNode := d.Root.FindFocused()
+	eventNode := d.FocusedNode
 	if eventNode == nil {
 		log.Logf("DOM: DispatchEvent - no focused node, fallback to root node")
 		// if no focused node, just send to root node
 		eventNode = d.Root
 	}
 
-	log.Logf("DOM: DispatchKeyDownEvent keyType='%s' key='%s' to focused node %s", keyEvent.KeyType, keyEvent.Runes, eventNode.Type)
+	log.Logf("DOM: DispatchKeyDownEvent keyType='%s' key='%s' to focused node %s", keyEvent.KeyType, string(keyEvent.Runes), eventNode.Type)
 
 	// Create the event
 	event := &DOMEvent{
@@ -92,97 +156,128 @@ func (d *DOM) DispatchKeyDownEvent(keyEvent *KeydownEvent) {
 		Target:        eventNode,
 		CurrentTarget: eventNode,
 		KeydownEvent:  keyEvent,
-		BubblePhase:   false,
 	}
 
-	// Handle the event at the target and bubble up
-	d.handleEventBubbling(eventNode, event)
+	// a key that is neither a binding nor the start of one ends the sequence
+	pending := d.pendingKeys()
+	d.keys = keySequence{}
+
+	d.dispatch(eventNode, event)
 	if !event.DefaultPrevented {
 		// handle default event
-		d.handleDefault(eventNode, event)
+		d.handleDefault(eventNode, event, pending)
 	}
 }
 
-// handleEventBubbling handles event bubbling up the DOM tree
-func (d *DOM) handleEventBubbling(node *Node, event *DOMEvent) {
-	if node == nil || event.PropagationStopped {
-		log.Logf("DOM: handleEventBubbling - node is nil or event stopped")
-		return
+// dispatch runs event through the phases of a W3C dispatch along the
+// path from the root to target:
+//   - capture: the capture listeners of the DOM, then the capture
+//     handlers (OnKeyDownCapture...) from the root down to the parent of target
+//   - target: the capture then the bubble handler of target
+//   - bubble: the bubble handlers (OnKeyDown...) from the parent of target
+//     up to the root, then the bubble listeners of the DOM
+//
+// StopPropagation skips the nodes after the current one,
+// StopImmediatePropagation the handlers after the current one.
+func (d *DOM) dispatch(target *Node, event *DOMEvent) {
+	var path []*Node // from target up to the root
+	for n := target; n != nil; n = n.Parent {
+		path = append(path, n)
 	}
 
-	// Set current target
-	event.CurrentTarget = node
+	event.Phase = PhaseCapturing
+	event.CurrentTarget = nil
+	d.Listeners.run(event, true)
+	for i := len(path) - 1; i > 0 && !event.PropagationStopped; i-- {
+		event.CurrentTarget = path[i]
+		if handler := path[i].GetCaptureEventHandler(event.Type); handler != nil {
+			handler(event)
+		}
+	}
+	if event.PropagationStopped {
+		log.Logf("DOM: dispatch - %s stopped in capture phase", event.Type)
+		return
+	}
 
-	handler := node.GetEventHandler(event.Type)
-	if handler != nil {
-		log.Logf("DOM: handleEventBubbling - calling handler for node %s", node.Type)
+	event.Phase = PhaseAtTarget
+	event.CurrentTarget = target
+	if handler := target.GetCaptureEventHandler(event.Type); handler != nil {
 		handler(event)
-	} else {
-		log.Logf("DOM: handleEventBubbling - no handler found for node %s", node.Type)
 	}
-
-	// If event wasn't stopped, bubble to parent
-	log.Logf("DOM: handleEventBubbling - checking bubbling conditions: stopPropagation=%t, hasParent=%t",
-		event.PropagationStopped, node.Parent != nil)
-	if !event.PropagationStopped && node.Parent != nil {
-		log.Logf("DOM: handleEventBubbling - bubbling to parent %s", node.Parent.Type)
-		event.BubblePhase = true
-		d.handleEventBubbling(node.Parent, event)
-		return
+	if handler := target.GetEventHandler(event.Type); handler != nil && !event.ImmediatePropagationStopped {
+		handler(event)
 	}
 
-	log.Logf("DOM: handleEventBubbling - reached end of bubbling chain (stopPropagation=%t, hasParent=%t)",
-		event.PropagationStopped, node.Parent != nil)
+	event.Phase = PhaseBubbling
+	event.BubblePhase = true
+	for _, node := range path[1:] {
+		if event.PropagationStopped {
+			log.Logf("DOM: dispatch - %s stopped before %s", event.Type, node.Type)
+			return
+		}
+		event.CurrentTarget = node
+		if handler := node.GetEventHandler(event.Type); handler != nil {
+			handler(event)
+		}
+	}
+	if !event.PropagationStopped {
+		event.CurrentTarget = nil
+		d.Listeners.run(event, false)
+	}
 }
 
-func (d *DOM) handleDefault(node *Node, event *DOMEvent) {
+// handleDefault runs the default behavior of event: text fields edit
+// their text, then the keymaps bind the keys left, see KeyMapProps, then
+// the keys move the focus and scroll. pending is the key sequence
+// pressed before.
+func (d *DOM) handleDefault(node *Node, event *DOMEvent, pending []string) {
 	if event.Type == EventTypeKeydown {
 		keyEvent := event.KeydownEvent
 		if keyEvent == nil {
 			return
 		}
+		if node.Type == ElementTypeTextArea && d.handleTextAreaKey(node, keyEvent) {
+			return
+		}
+		if node.Type == ElementTypeInput && d.handleInputKey(node, keyEvent) {
+			return
+		}
+		if !event.PropagationStopped && d.runKeymap(node, event, pending) {
+			return
+		}
+		if node.Type != ElementTypeScrollView && d.moveFocusSpatially(keyEvent.KeyType) {
+			return
+		}
 		switch keyEvent.KeyType {
+		case KeyTypeTab, KeyTypeShiftTab:
+			direction := 1
+			if keyEvent.KeyType == KeyTypeShiftTab {
+				direction = -1
+			}
+			if target := d.MoveTabFocus(direction); target != nil {
+				d.ScrollIntoView(target)
+			}
 		case KeyTypeUp, KeyTypeDown:
+			if node.Type == ElementTypeScrollView {
+				// a focused scroll view scrolls by a line
+				d.scrollByKey(node, keyEvent.KeyType)
+				return
+			}
 			// handle focus navigation
 			direction := 1
 			if keyEvent.KeyType == KeyTypeUp {
 				direction = -1
 			}
+			target := d.focusTarget(direction)
 			if d.HandleFocusNavigation(event, direction) {
-				return
-			}
-		case KeyTypeLeft, KeyTypeRight:
-			// move inside the input
-			if node.Type == ElementTypeInput {
-				props := ExtractProps[InputProps](node.Props)
-				if props.OnCursorMove != nil {
-					delta := 1
-					if keyEvent.KeyType == KeyTypeLeft {
-						delta = -1
-					}
-					props.OnCursorMove(props.CursorPosition + delta)
+				if target != nil {
+					d.ScrollIntoView(target)
 				}
+				return
 			}
-		default:
-			// handle input
-			if node.Type == ElementTypeInput {
-				props := ExtractProps[InputProps](node.Props)
-
-				// Get current value from the model if available, otherwise from props
-				currentValue := props.Value
-
-				// Update value based on key input
-				newValue, newPos := UpdateInputValue(currentValue, props.CursorPosition, keyEvent)
-				if newValue != currentValue {
-					if props.OnChange != nil {
-						props.OnChange(newValue)
-					}
-				}
-				if newPos != props.CursorPosition {
-					if props.OnCursorMove != nil {
-						props.OnCursorMove(newPos)
-					}
-				}
+		case KeyTypePgUp, KeyTypePgDown, KeyTypeHome, KeyTypeEnd:
+			if view := node.closestScrollView(); view != nil {
+				d.scrollByKey(view, keyEvent.KeyType)
 			}
 		}
 	}
@@ -0,0 +1,105 @@
+package dom
+
+import (
+	"reflect"
+	"testing"
+)
+
+// phaseTree builds root > parent > input, recording the handlers called.
+// stop names the handler that calls stop on the event.
+func phaseTree(calls *[]string, stop string, stopFn func(*DOMEvent)) *DOM {
+	handler := func(name string) func(*DOMEvent) {
+		return func(e *DOMEvent) {
+			*calls = append(*calls, name)
+			if name == stop {
+				stopFn(e)
+			}
+		}
+	}
+	input := Input(InputProps{
+		Focused:          true,
+		OnKeyDown:        handler("input"),
+		OnKeyDownCapture: handler("input capture"),
+	})
+	parent := Div(DivProps{OnKeyDown: handler("parent"), OnKeyDownCapture: handler("parent capture")}, input)
+	root := Div(DivProps{OnKeyDown: handler("root"), OnKeyDownCapture: handler("root capture")}, parent)
+	d := NewDOM(root, &Window{})
+	d.AddEventListener(EventTypeKeydown, handler("listener capture"), true)
+	d.AddEventListener(EventTypeKeydown, handler("listener"), false)
+	d.AddEventListener(EventTypeKeydown, handler("listener 2"), false)
+	return d
+}
+
+func TestDispatchPhases(t *testing.T) {
+	tests := []struct {
+		name     string
+		stop     string
+		stopFn   func(*DOMEvent)
+		expected []string
+	}{
+		{
+			name: "All",
+			expected: []string{
+				"listener capture", "root capture", "parent capture",
+				"input capture", "input",
+				"parent", "root", "listener", "listener 2",
+			},
+		},
+		{
+			name:     "StopInCapture",
+			stop:     "parent capture",
+			stopFn:   (*DOMEvent).StopPropagation,
+			expected: []string{"listener capture", "root capture", "parent capture"},
+		},
+		{
+			name:     "StopAtTargetKeepsTargetHandlers",
+			stop:     "input capture",
+			stopFn:   (*DOMEvent).StopPropagation,
+			expected: []string{"listener capture", "root capture", "parent capture", "input capture", "input"},
+		},
+		{
+			name:     "StopImmediateAtTarget",
+			stop:     "input capture",
+			stopFn:   (*DOMEvent).StopImmediatePropagation,
+			expected: []string{"listener capture", "root capture", "parent capture", "input capture"},
+		},
+		{
+			name:   "StopImmediateInListeners",
+			stop:   "listener",
+			stopFn: (*DOMEvent).StopImmediatePropagation,
+			expected: []string{
+				"listener capture", "root capture", "parent capture",
+				"input capture", "input",
+				"parent", "root", "listener",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			d := phaseTree(&calls, tt.stop, tt.stopFn)
+			d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeEsc})
+			if !reflect.DeepEqual(calls, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, calls)
+			}
+		})
+	}
+}
+
+func TestEventListenersSurviveRerender(t *testing.T) {
+	var calls []string
+	d := NewDOM(Div(DivProps{}), &Window{})
+	id := d.AddEventListener(EventTypeKeydown, func(e *DOMEvent) {
+		calls = append(calls, string(e.KeydownEvent.KeyType))
+	}, false)
+
+	next := NewDOM(Div(DivProps{}), &Window{})
+	next.Inherit(d)
+	next.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeF1})
+	next.RemoveEventListener(id)
+	next.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeF2})
+
+	if expected := []string{"f1"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
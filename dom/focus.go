@@ -2,52 +2,180 @@ package dom
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/xhd2015/go-dom-tui/log"
 )
 
 // SetFocus sets focus to a specific node
 func (d *DOM) SetFocus(node *Node) {
-	if node.IsFocused() {
+	if node == d.FocusedNode {
 		return
 	}
+	if d.FocusedNode != nil {
+		log.Logf("DOM: SetFocus - clearing focus from %s", d.FocusedNode.Type)
+	}
+	d.moveFocusTo(node)
+}
+
+// takeFocus makes node the focused node of the DOM, which keys go to.
+// When the DOM owns the focus, the node is also rendered focused; otherwise
+// the app renders it focused once its OnFocus handler set its state.
+func (d *DOM) takeFocus(node *Node) {
+	if !d.OwnFocus {
+		d.trackFocusBy(func(n *Node) bool { return n == node })
+		return
+	}
+	if d.FocusedNode != nil {
+		d.FocusedNode.focused = false
+	}
+	if node != nil {
+		node.focused = true
+	}
+	d.trackFocus()
+}
 
-	prev := d.Root.FindFocused()
+// FocusedKey returns the Key of the focused node, "" when no node with
+// a key is focused. With OwnFocus, apps give the nodes they need to tell
+// apart a key and read the focus from here instead of keeping it.
+func (d *DOM) FocusedKey() string {
+	if d.FocusedNode == nil {
+		return ""
+	}
+	return d.FocusedNode.Key
+}
+
+// IsFocused reports whether node has the focus
+func (d *DOM) IsFocused(node *Node) bool {
+	return node != nil && node.IsFocused()
+}
 
-	// Clear previous focus
-	if prev != nil {
-		if prev == node {
+// FocusKey focuses the first node with the given key that can take the
+// focus, and reports whether there is one
+func (d *DOM) FocusKey(key string) bool {
+	var found *Node
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		if node == nil || found != nil {
 			return
 		}
-		log.Logf("DOM: SetFocus - clearing focus from %s", prev.Type)
-		prev.SetFocused(false)
+		if node.Key == key && node.IsFocusable() {
+			found = node
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
 	}
-
-	node.SetFocused(true)
+	walk(d.Root)
+	if found == nil {
+		return false
+	}
+	d.SetFocus(found)
+	return true
 }
 
 func (d *DOM) MoveFocus(direction int) bool {
-	curFocus := d.FocusedNode
-	nextFocus := d.NextFocuseable
-	if direction < 0 {
-		nextFocus = d.PreviousFocuseable
-		if nextFocus == nil {
-			nextFocus = d.LastFocusable
+	d.moveFocusTo(d.focusTarget(direction))
+	return true
+}
+
+// moveFocusTo blurs the focused node and focuses next
+func (d *DOM) moveFocusTo(next *Node) {
+	cur := d.FocusedNode
+	if cur == next {
+		return
+	}
+	if cur != nil {
+		cur.SetFocused(false)
+	}
+	if next != nil {
+		next.SetFocused(true)
+	}
+	d.takeFocus(next)
+}
+
+// TabOrder returns the nodes Tab and Shift-Tab move the focus through,
+// like a browser does: the focusable nodes with a positive TabIndex first,
+// in ascending order, then the others in document order. Nodes with a
+// negative TabIndex are left out, they are only focused programmatically.
+// Inside a focus scope, only the nodes of the scope are returned.
+func (d *DOM) TabOrder() []*Node {
+	return tabOrder(d.focusRoot())
+}
+
+// tabOrder returns the tab order of the subtree of root
+func tabOrder(root *Node) []*Node {
+	var positive, rest []*Node
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		if node == nil {
+			return
+		}
+		if node.IsFocusable() {
+			index, _ := node.GetTabIndex()
+			switch {
+			case index > 0:
+				positive = append(positive, node)
+			case index == 0:
+				rest = append(rest, node)
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
 		}
-	} else if nextFocus == nil {
-		nextFocus = d.FirstFocusable
 	}
+	walk(root)
 
-	if curFocus == nextFocus {
-		return true
+	sort.SliceStable(positive, func(i, j int) bool {
+		a, _ := positive[i].GetTabIndex()
+		b, _ := positive[j].GetTabIndex()
+		return a < b
+	})
+	return append(positive, rest...)
+}
+
+// tabTarget returns the node Tab (direction 1) or Shift-Tab (direction -1)
+// moves the focus to, wrapping around the tab order
+func (d *DOM) tabTarget(direction int) *Node {
+	order := d.TabOrder()
+	if len(order) == 0 {
+		return nil
 	}
-	if curFocus != nil {
-		curFocus.SetFocused(false)
+	for i, node := range order {
+		if node == d.FocusedNode {
+			return order[(i+direction+len(order))%len(order)]
+		}
 	}
-	if nextFocus != nil {
-		nextFocus.SetFocused(true)
+	if direction < 0 {
+		return order[len(order)-1]
 	}
-	return true
+	return order[0]
+}
+
+// MoveTabFocus moves the focus to the next node in TabOrder, or to the
+// previous one when direction is negative, and returns it
+func (d *DOM) MoveTabFocus(direction int) *Node {
+	target := d.tabTarget(direction)
+	d.moveFocusTo(target)
+	return target
+}
+
+// focusTarget returns the node MoveFocus moves the focus to
+func (d *DOM) focusTarget(direction int) *Node {
+	if trap := d.focusTrap(); trap != nil {
+		return d.trappedFocusTarget(trap, direction)
+	}
+	if direction < 0 {
+		if d.PreviousFocuseable != nil {
+			return d.PreviousFocuseable
+		}
+		return d.LastFocusable
+	}
+	if d.NextFocuseable != nil {
+		return d.NextFocuseable
+	}
+	return d.FirstFocusable
 }
 
 // MoveFocus moves focus in the tab order
@@ -189,7 +317,12 @@ func (c *Node) SetFocused(focused bool) {
 	}
 }
 
+// IsFocused reports whether the node is focused: it has the focus the
+// DOM owns, or its focused prop is set
 func (c *Node) IsFocused() bool {
+	if c.focused {
+		return true
+	}
 	if c.Props != nil {
 		if focused, ok := c.Props.Get("focused"); ok {
 			if focusedBool, ok := focused.(bool); ok {
@@ -200,25 +333,54 @@ func (c *Node) IsFocused() bool {
 	return false
 }
 
+// IsFocusable reports whether the node can take the focus: when its
+// focusable prop says so, or by default inputs, text areas and nodes with
+// a TabIndex
 func (c *Node) IsFocusable() bool {
 	if c.Props != nil {
 		if focusable, ok := c.Props.Get("focusable"); ok {
 			if pbool, ok := focusable.(*bool); ok {
 				if pbool == nil {
 					// default value
-					if c.Type == ElementTypeInput {
+					if c.Type == ElementTypeInput || c.Type == ElementTypeTextArea {
 						return true
 					}
-					return false
+					return c.hasTabIndex()
 				}
 				return *pbool
 			}
 			if focusableBool, ok := focusable.(bool); ok {
-				return focusableBool
+				return focusableBool || c.hasTabIndex()
 			} else {
 				panic(fmt.Errorf("focusable expect bool, got %T", focusable))
 			}
 		}
 	}
-	return false
+	return c.hasTabIndex()
+}
+
+// GetTabIndex returns the tabIndex prop of the node, 0 and false if unset
+func (c *Node) GetTabIndex() (int, bool) {
+	if c.Props == nil {
+		return 0, false
+	}
+	tabIndex, ok := c.Props.Get("tabIndex")
+	if !ok {
+		return 0, false
+	}
+	switch v := tabIndex.(type) {
+	case *int:
+		if v == nil {
+			return 0, false
+		}
+		return *v, true
+	case int:
+		return v, true
+	}
+	return 0, false
+}
+
+func (c *Node) hasTabIndex() bool {
+	_, ok := c.GetTabIndex()
+	return ok
 }
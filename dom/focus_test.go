@@ -0,0 +1,132 @@
+package dom
+
+import (
+	"reflect"
+	"testing"
+)
+
+// focusApp renders fields named by the keys of tabIndex, keeping which
+// one is focused like an app would
+type focusApp struct {
+	names    []string
+	tabIndex map[string]*int
+	focused  string
+}
+
+func (a *focusApp) render() *DOM {
+	var children []*Node
+	for _, name := range a.names {
+		name := name
+		children = append(children, Input(InputProps{
+			Placeholder: name,
+			TabIndex:    a.tabIndex[name],
+			Focused:     a.focused == name,
+			OnFocus:     func() { a.focused = name },
+		}))
+	}
+	return NewDOM(Div(DivProps{}, children...), &Window{})
+}
+
+func (a *focusApp) press(key KeyType) string {
+	a.render().DispatchKeyDownEvent(&KeydownEvent{KeyType: key})
+	return a.focused
+}
+
+func TestTabOrder(t *testing.T) {
+	app := &focusApp{
+		names: []string{"a", "b", "c", "d", "e"},
+		tabIndex: map[string]*int{
+			"b": TabIndex(2),
+			"c": TabIndex(-1),
+			"d": TabIndex(1),
+		},
+	}
+
+	var order []string
+	for _, node := range app.render().TabOrder() {
+		order = append(order, ExtractProps[InputProps](node.Props).Placeholder)
+	}
+	if expected := []string{"d", "b", "a", "e"}; !reflect.DeepEqual(order, expected) {
+		t.Fatalf("expected tab order %v, got %v", expected, order)
+	}
+
+	var tabs []string
+	for i := 0; i < 5; i++ {
+		tabs = append(tabs, app.press(KeyTypeTab))
+	}
+	if expected := []string{"d", "b", "a", "e", "d"}; !reflect.DeepEqual(tabs, expected) {
+		t.Errorf("expected tab to visit %v, got %v", expected, tabs)
+	}
+
+	var backTabs []string
+	for i := 0; i < 2; i++ {
+		backTabs = append(backTabs, app.press(KeyTypeShiftTab))
+	}
+	if expected := []string{"e", "a"}; !reflect.DeepEqual(backTabs, expected) {
+		t.Errorf("expected shift+tab to visit %v, got %v", expected, backTabs)
+	}
+}
+
+func TestTabIndexMakesFocusable(t *testing.T) {
+	if !Div(DivProps{TabIndex: TabIndex(0)}).IsFocusable() {
+		t.Errorf("expected a div with a tab index to be focusable")
+	}
+	if Button(ButtonProps{TabIndex: TabIndex(0), Focusable: Focusable(false)}).IsFocusable() {
+		t.Errorf("expected focusable false to win over the tab index")
+	}
+}
+
+func TestArrowsSkipNegativeTabIndex(t *testing.T) {
+	app := &focusApp{names: []string{"a", "b", "c"}, tabIndex: map[string]*int{"b": TabIndex(-1)}, focused: "a"}
+	if got := app.press(KeyTypeDown); got != "c" {
+		t.Errorf("expected down to skip b, got %q", got)
+	}
+}
+
+func TestOwnFocus(t *testing.T) {
+	var changed []string
+	render := func(prev *DOM, focused string) *DOM {
+		var children []*Node
+		for _, name := range []string{"a", "b", "c"} {
+			name := name
+			input := Input(InputProps{
+				Focused:  focused == name,
+				OnChange: func(string) { changed = append(changed, name) },
+			})
+			input.Key = name
+			children = append(children, input)
+		}
+		d := NewDOM(Div(DivProps{}, children...), &Window{})
+		d.OwnFocus = true
+		d.Inherit(prev)
+		return d
+	}
+
+	d := render(nil, "")
+	if d.FocusedKey() != "" {
+		t.Fatalf("expected nothing focused, got %q", d.FocusedKey())
+	}
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeTab})
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeTab})
+	if d.FocusedKey() != "b" {
+		t.Fatalf("expected b focused, got %q", d.FocusedKey())
+	}
+
+	d = render(d, "")
+	if d.FocusedKey() != "b" || !d.IsFocused(d.Root.Children[1]) {
+		t.Fatalf("expected the focus kept on b across renders, got %q", d.FocusedKey())
+	}
+	d.DispatchKeyDownEvent(&KeydownEvent{Runes: []rune("x")})
+	if len(changed) != 1 || changed[0] != "b" {
+		t.Errorf("expected typing to reach b, got %v", changed)
+	}
+
+	d = render(d, "c")
+	if d.FocusedKey() != "c" {
+		t.Errorf("expected the Focused prop to override, got %q", d.FocusedKey())
+	}
+	d = render(d, "")
+	if !d.FocusKey("a") || d.FocusedKey() != "a" {
+		t.Errorf("expected a focused by key, got %q", d.FocusedKey())
+	}
+}
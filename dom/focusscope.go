@@ -0,0 +1,132 @@
+package dom
+
+// focusTrap returns the focus scope focus traversal is confined to: the
+// innermost one with Trap around the focused node, or the last one with Trap
+// in the tree when the focus is outside them all, which is usually the
+// dialog drawn on top. It returns nil when no focus scope traps the focus.
+func (d *DOM) focusTrap() *Node {
+	for n := d.FocusedNode; n != nil; n = n.Parent {
+		if n.Type == ElementTypeFocusScope && ExtractProps[FocusScopeProps](n.Props).Trap {
+			return n
+		}
+	}
+	for i := len(d.focusScopes) - 1; i >= 0; i-- {
+		if ExtractProps[FocusScopeProps](d.focusScopes[i].Props).Trap {
+			return d.focusScopes[i]
+		}
+	}
+	return nil
+}
+
+// focusRoot returns the subtree focus traversal walks
+func (d *DOM) focusRoot() *Node {
+	if trap := d.focusTrap(); trap != nil {
+		return trap
+	}
+	return d.Root
+}
+
+// canFocus reports whether node is allowed to take the focus by the focus trap
+func (d *DOM) canFocus(node *Node) bool {
+	trap := d.focusTrap()
+	return trap == nil || node.isInside(trap)
+}
+
+// isInside reports whether ancestor is c or one of its ancestors
+func (c *Node) isInside(ancestor *Node) bool {
+	for n := c; n != nil; n = n.Parent {
+		if n == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// trappedFocusTarget returns the node the arrows move the focus to inside
+// trap, like focusTarget does in the whole tree
+func (d *DOM) trappedFocusTarget(trap *Node, direction int) *Node {
+	var nodes []*Node
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		if node == nil {
+			return
+		}
+		if index, _ := node.GetTabIndex(); index >= 0 && node.IsFocusable() {
+			nodes = append(nodes, node)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(trap)
+	if len(nodes) == 0 {
+		return d.FocusedNode
+	}
+	for i, node := range nodes {
+		if node == d.FocusedNode {
+			return nodes[(i+direction+len(nodes))%len(nodes)]
+		}
+	}
+	if direction < 0 {
+		return nodes[len(nodes)-1]
+	}
+	return nodes[0]
+}
+
+// UpdateFocusScopes runs what focus scopes do when they mount and unmount,
+// comparing with prev, the DOM of the previous render:
+//   - a scope mounting remembers the node focused before, and with
+//     AutoFocus focuses its first node in tab order
+//   - a scope unmounting focuses the node it remembered again,
+//     unless the app focused another node meanwhile
+//
+// It reports whether it moved the focus. The DOM is then focused as it
+// should be, but the handlers may have changed the state of the app,
+// which shows when the tree is rendered again.
+func (d *DOM) UpdateFocusScopes(prev *DOM) bool {
+	var prevReturns map[string]string
+	prevScopes := make(map[string]bool)
+	if prev != nil {
+		prevReturns = prev.focusReturns
+		for _, scope := range prev.focusScopes {
+			prevScopes[scope.path] = true
+		}
+	}
+
+	moved := false
+	d.focusReturns = make(map[string]string, len(d.focusScopes))
+	for _, scope := range d.focusScopes {
+		if prevScopes[scope.path] {
+			d.focusReturns[scope.path] = prevReturns[scope.path]
+			continue
+		}
+		// mounted
+		if prev != nil && prev.FocusedNode != nil {
+			d.focusReturns[scope.path] = prev.FocusedNode.path
+		}
+		props := ExtractProps[FocusScopeProps](scope.Props)
+		if !props.AutoFocus || (d.FocusedNode != nil && d.FocusedNode.isInside(scope)) {
+			continue
+		}
+		if order := tabOrder(scope); len(order) > 0 {
+			d.moveFocusTo(order[0])
+			moved = true
+		}
+	}
+
+	if prev == nil || d.FocusedNode != nil {
+		return moved
+	}
+	// unmounted, outer scopes first as they remember the focus from before the inner ones
+	for _, scope := range prev.focusScopes {
+		if _, ok := d.focusReturns[scope.path]; ok || prevReturns[scope.path] == "" {
+			continue
+		}
+		target := d.Root.findByPath(prevReturns[scope.path])
+		if target != nil && target.IsFocusable() && d.canFocus(target) {
+			d.moveFocusTo(target)
+			return true
+		}
+	}
+	return moved
+}
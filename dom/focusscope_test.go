@@ -0,0 +1,115 @@
+package dom
+
+import (
+	"strings"
+	"testing"
+)
+
+// dialogApp renders a list of fields, and over it a dialog in a focus scope when open
+type dialogApp struct {
+	open      bool
+	autoFocus bool
+	trap      bool
+	focused   string
+}
+
+func (a *dialogApp) field(name string) *Node {
+	return Input(InputProps{
+		Placeholder: name,
+		Focused:     a.focused == name,
+		OnFocus:     func() { a.focused = name },
+	})
+}
+
+func (a *dialogApp) render(prev *DOM) *DOM {
+	children := []*Node{Div(DivProps{}, a.field("a"), a.field("b"))}
+	if a.open {
+		children = append(children, FocusScope(FocusScopeProps{Trap: a.trap, AutoFocus: a.autoFocus}, a.field("x"), a.field("y")))
+	}
+	d := NewDOM(ZDiv(DivProps{}, children...), &Window{})
+	d.Inherit(prev)
+	if d.UpdateFocusScopes(prev) {
+		next := NewDOM(ZDiv(DivProps{}, children...), &Window{})
+		next.Inherit(d)
+		return next
+	}
+	return d
+}
+
+func TestFocusScopeTrapsAndRestores(t *testing.T) {
+	app := &dialogApp{autoFocus: true, trap: true, focused: "b"}
+	d := app.render(nil)
+
+	app.open = true
+	d = app.render(d)
+	if app.focused != "x" {
+		t.Fatalf("expected the dialog to take the focus, got %q", app.focused)
+	}
+
+	var visited []string
+	for _, key := range []KeyType{KeyTypeTab, KeyTypeTab, KeyTypeShiftTab, KeyTypeDown, KeyTypeDown} {
+		d = app.render(d)
+		d.DispatchKeyDownEvent(&KeydownEvent{KeyType: key})
+		visited = append(visited, app.focused)
+	}
+	if got, expected := strings.Join(visited, " "), "y x y x y"; got != expected {
+		t.Fatalf("expected the focus to stay in the dialog, visiting %q, got %q", expected, got)
+	}
+
+	app.open = false
+	app.render(d)
+	if app.focused != "b" {
+		t.Errorf("expected the focus back on b, got %q", app.focused)
+	}
+}
+
+func TestFocusScopeTrapsFocusOutside(t *testing.T) {
+	app := &dialogApp{open: true, trap: true, focused: "a"}
+	d := app.render(nil)
+	if app.focused != "a" {
+		t.Fatalf("expected no auto focus, got %q", app.focused)
+	}
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeTab})
+	if app.focused != "x" {
+		t.Errorf("expected tab to enter the dialog, got %q", app.focused)
+	}
+}
+
+func TestFocusScopeWithoutTrap(t *testing.T) {
+	app := &dialogApp{open: true, focused: "a"}
+	d := app.render(nil)
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeTab})
+	if app.focused != "b" {
+		t.Errorf("expected tab to move to the next field, got %q", app.focused)
+	}
+
+	d = app.render(d)
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeTab})
+	d = app.render(d)
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeTab})
+	d = app.render(d)
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeTab})
+	if app.focused != "a" {
+		t.Errorf("expected tab to leave the scope, got %q", app.focused)
+	}
+}
+
+func TestFocusScopeAutoFocusTakesKeys(t *testing.T) {
+	var target string
+	field := func(name string, focused bool) *Node {
+		return Input(InputProps{
+			Focused:   focused,
+			OnKeyDown: func(e *DOMEvent) { target = name },
+		})
+	}
+	prev := NewDOM(Div(DivProps{}, field("a", true)), &Window{})
+	// the app has not rendered the focus the scope moved yet
+	d := NewDOM(Div(DivProps{}, field("a", true), FocusScope(FocusScopeProps{AutoFocus: true}, field("x", false))), &Window{})
+	d.Inherit(prev)
+	d.UpdateFocusScopes(prev)
+
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeEnter})
+	if target != "x" {
+		t.Errorf("expected the key to go to the field the scope focused, got %q", target)
+	}
+}
@@ -0,0 +1,102 @@
+package dom
+
+// Rect is an area of the screen in terminal cells
+type Rect struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// Contains reports whether cell (x, y) falls inside the rect
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Inset shrinks the rect by the given insets, never below zero size
+func (r Rect) Inset(in Insets) Rect {
+	res := Rect{
+		X:      r.X + in.Left,
+		Y:      r.Y + in.Top,
+		Width:  r.Width - in.Left - in.Right,
+		Height: r.Height - in.Top - in.Bottom,
+	}
+	if res.Width < 0 {
+		res.Width = 0
+	}
+	if res.Height < 0 {
+		res.Height = 0
+	}
+	return res
+}
+
+// Insets are the sizes of the four edges of a box, like border or padding
+type Insets struct {
+	Top    int
+	Right  int
+	Bottom int
+	Left   int
+}
+
+// Position places a child of a ZDiv, which otherwise overlays it at the
+// top-left corner. Top, Left, Right and Bottom are offsets from the ZDiv's
+// edges: setting Right without Left anchors the child to the right edge,
+// Bottom without Top to the bottom one, so two of them anchor it to a corner.
+// CenterX and CenterY center the child on that axis instead, with the
+// offsets shifting it from the center.
+type Position struct {
+	Top    *int
+	Left   *int
+	Right  *int
+	Bottom *int
+
+	CenterX bool
+	CenterY bool
+}
+
+// PositionOf returns the Position prop of a node, if its props have one
+func PositionOf(props Props) Position {
+	if props == nil {
+		return Position{}
+	}
+	v, _ := props.Get("position")
+	pos, _ := v.(Position)
+	return pos
+}
+
+// SizeUnit tells how the Value of a Size is measured
+type SizeUnit uint8
+
+const (
+	SizeAuto    SizeUnit = iota // not set, sized by the content
+	SizeCells                   // a number of terminal cells
+	SizePercent                 // a percentage of the parent's size
+)
+
+// Size is a length along one axis. The zero Size is auto.
+type Size struct {
+	Value int
+	Unit  SizeUnit
+}
+
+// Cells returns a Size of n terminal cells
+func Cells(n int) Size {
+	return Size{Value: n, Unit: SizeCells}
+}
+
+// Percent returns a Size of p percent of the parent's size
+func Percent(p int) Size {
+	return Size{Value: p, Unit: SizePercent}
+}
+
+// Resolve returns the size in cells given the parent's size,
+// and false if the size is auto
+func (s Size) Resolve(parent int) (int, bool) {
+	switch s.Unit {
+	case SizeCells:
+		return s.Value, true
+	case SizePercent:
+		return parent * s.Value / 100, true
+	}
+	return 0, false
+}
@@ -69,6 +69,58 @@ func UpdateInputValue(currentValue string, pos int, e *KeydownEvent) (string, in
 	}
 }
 
+// handleInputKey applies a key press to an input and calls its OnChange,
+// OnCursorMove and OnSelect. Clipboard keys, keys that move the cursor
+// sideways or select, and keys pressed while text is selected are handled
+// like in a text area, the others by UpdateInputValue.
+// It returns false for the keys left to focus navigation and scrolling,
+// and for the keys that do nothing, like ctrl+c without a selection,
+// which are left to the keymaps.
+func (d *DOM) handleInputKey(node *Node, keyEvent *KeydownEvent) bool {
+	switch keyEvent.KeyType {
+	case KeyTypeUp, KeyTypeDown, KeyTypePgUp, KeyTypePgDown, KeyTypeTab, KeyTypeShiftTab:
+		return false
+	}
+	props := ExtractProps[InputProps](node.Props)
+	before := TextEdit{Value: props.Value, Cursor: props.CursorPosition, Anchor: props.SelectionAnchor}
+
+	after, ok := d.clipboardEdit(before, keyEvent, 0)
+	if !ok && editsLikeTextArea(before, keyEvent) {
+		after, ok = EditText(before, keyEvent, 0)
+	}
+	if !ok {
+		value, pos := UpdateInputValue(before.Value, before.Cursor, keyEvent)
+		if value == before.Value && pos == before.Cursor && len(keyEvent.Runes) == 0 {
+			return false
+		}
+		after = TextEdit{Value: value, Cursor: pos}
+		if value == before.Value && pos == before.Cursor {
+			after.Anchor = before.Anchor
+		}
+	}
+	notifyTextEdit(before, after, props.OnChange, props.OnCursorMove, props.OnSelect)
+	return true
+}
+
+// editsLikeTextArea reports whether an input leaves a key press to EditText
+func editsLikeTextArea(t TextEdit, e *KeydownEvent) bool {
+	switch e.KeyType {
+	case KeyTypeLeft, KeyTypeRight, KeyTypeHome, KeyTypeEnd:
+		return true
+	case KeyTypeEnter:
+		// inputs have a single line
+		return false
+	}
+	if _, selecting := unshifted[e.KeyType]; selecting {
+		return true
+	}
+	if e.Alt && len(e.Runes) == 1 && e.Runes[0] == 'a' {
+		return true
+	}
+	_, _, selected := t.Selection()
+	return selected
+}
+
 // handleDeleteBackWord deletes back a word from the current position
 // returns new string and new position
 func handleDeleteBackWord(currentValue string, pos int) (string, int) {
@@ -77,7 +129,7 @@ func handleDeleteBackWord(currentValue string, pos int) (string, int) {
 	}
 	runes := []rune(currentValue)
 
-	// Skip trailing spaces
+	// Skip trailing spaces, they are kept
 	if pos > len(runes) {
 		pos = len(runes)
 	}
@@ -93,7 +145,7 @@ func handleDeleteBackWord(currentValue string, pos int) (string, int) {
 		l--
 	}
 
-	newStr := string(runes[:l]) + string(runes[pos:])
+	newStr := string(runes[:l]) + string(runes[p:])
 	newPos := l
 
 	return newStr, newPos
@@ -0,0 +1,137 @@
+package dom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Key is a key press: the key and the modifiers held with it
+type Key struct {
+	// Code is the key: a name like "up", "f1" or "enter" for the
+	// keys in KeyType, or the text typed, like "a" or "A"
+	Code string
+
+	Ctrl  bool
+	Alt   bool
+	Shift bool
+	Meta  bool
+}
+
+// String returns the canonical form of the key: the modifiers in the
+// order ctrl, alt, shift, meta then the code, joined by "+", like "ctrl+shift+up"
+func (k Key) String() string {
+	if k.Code == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, m := range []struct {
+		on   bool
+		name string
+	}{{k.Ctrl, "ctrl"}, {k.Alt, "alt"}, {k.Shift, "shift"}, {k.Meta, "meta"}} {
+		if m.on {
+			b.WriteString(m.name)
+			b.WriteByte('+')
+		}
+	}
+	b.WriteString(k.Code)
+	return b.String()
+}
+
+// IsRunes reports whether the key is text typed rather than a named key
+func (k Key) IsRunes() bool {
+	return k.Code != "" && !namedKeys[k.Code]
+}
+
+// keyAliases are the other names ParseKey accepts for a key
+var keyAliases = map[string]string{
+	"escape":   "esc",
+	"return":   "enter",
+	"del":      "delete",
+	"ins":      "insert",
+	"pageup":   "pgup",
+	"pagedown": "pgdown",
+	" ":        "space",
+}
+
+// namedKeys are the codes of the keys that do not type text
+var namedKeys = map[string]bool{
+	"enter": true, "backspace": true, "delete": true, "insert": true, "tab": true,
+	"esc": true, "space": true, "up": true, "down": true, "left": true, "right": true,
+	"pgup": true, "pgdown": true, "home": true, "end": true,
+	"f1": true, "f2": true, "f3": true, "f4": true, "f5": true, "f6": true, "f7": true,
+	"f8": true, "f9": true, "f10": true, "f11": true, "f12": true, "f13": true,
+	"f14": true, "f15": true, "f16": true, "f17": true, "f18": true, "f19": true, "f20": true,
+}
+
+// ParseKey parses the form Key.String returns, also accepting modifiers in
+// any order and in upper case, and aliases like "escape" or "pageup"
+func ParseKey(s string) (Key, error) {
+	var key Key
+	rest := s
+	for {
+		i := strings.Index(rest, "+")
+		if i <= 0 || i == len(rest)-1 {
+			// no modifier left, or "+" is the key itself
+			break
+		}
+		switch strings.ToLower(rest[:i]) {
+		case "ctrl":
+			key.Ctrl = true
+		case "alt":
+			key.Alt = true
+		case "shift":
+			key.Shift = true
+		case "meta":
+			key.Meta = true
+		default:
+			return Key{}, fmt.Errorf("invalid key %q: unknown modifier %q", s, rest[:i])
+		}
+		rest = rest[i+1:]
+	}
+	if rest == "" {
+		return Key{}, fmt.Errorf("invalid key %q: missing key", s)
+	}
+	if len([]rune(rest)) > 1 {
+		rest = strings.ToLower(rest)
+	}
+	if alias, ok := keyAliases[rest]; ok {
+		rest = alias
+	}
+	if len([]rune(rest)) > 1 && !namedKeys[rest] {
+		return Key{}, fmt.Errorf("invalid key %q: unknown key %q", s, rest)
+	}
+	key.Code = rest
+	return key, nil
+}
+
+// NewKeydownEvent creates the event of pressing key, filling KeyType,
+// Runes and Alt from it
+func NewKeydownEvent(key Key) *KeydownEvent {
+	e := &KeydownEvent{Key: key, Alt: key.Alt}
+	switch {
+	case key.IsRunes() && !key.Ctrl && !key.Meta:
+		e.Runes = []rune(key.Code)
+	case key.Code == "space" && !key.Ctrl && !key.Alt && !key.Meta:
+		e.KeyType = KeyTypeSpace
+		e.Runes = []rune{' '}
+	default:
+		e.KeyType = KeyType(key.String())
+	}
+	return e
+}
+
+// GetKey returns the key pressed. Events created without Key, setting
+// only KeyType or Runes, get it from them.
+func (e *KeydownEvent) GetKey() Key {
+	if e.Key.Code != "" {
+		return e.Key
+	}
+	if e.KeyType != "" {
+		key, err := ParseKey(string(e.KeyType))
+		if err == nil {
+			return key
+		}
+		return Key{Code: string(e.KeyType)}
+	}
+	return Key{Code: string(e.Runes), Alt: e.Alt}
+}
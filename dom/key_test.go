@@ -0,0 +1,80 @@
+package dom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Key
+		str      string
+	}{
+		{"up", Key{Code: "up"}, "up"},
+		{"a", Key{Code: "a"}, "a"},
+		{"A", Key{Code: "A"}, "A"},
+		{"ctrl+shift+up", Key{Code: "up", Ctrl: true, Shift: true}, "ctrl+shift+up"},
+		{"Shift+Ctrl+Up", Key{Code: "up", Ctrl: true, Shift: true}, "ctrl+shift+up"},
+		{"meta+alt+x", Key{Code: "x", Alt: true, Meta: true}, "alt+meta+x"},
+		{"ctrl++", Key{Code: "+", Ctrl: true}, "ctrl++"},
+		{"+", Key{Code: "+"}, "+"},
+		{"PageDown", Key{Code: "pgdown"}, "pgdown"},
+		{"escape", Key{Code: "esc"}, "esc"},
+		{"shift+f12", Key{Code: "f12", Shift: true}, "shift+f12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseKey(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+			if got.String() != tt.str {
+				t.Errorf("expected %q, got %q", tt.str, got.String())
+			}
+		})
+	}
+
+	for _, input := range []string{"", "ctrl+", "hyper+a", "upp"} {
+		if _, err := ParseKey(input); err == nil {
+			t.Errorf("expected %q to be invalid", input)
+		}
+	}
+}
+
+func TestNewKeydownEvent(t *testing.T) {
+	tests := []struct {
+		key     Key
+		keyType KeyType
+		runes   string
+	}{
+		{Key{Code: "a"}, "", "a"},
+		{Key{Code: "a", Alt: true}, "", "a"},
+		{Key{Code: "a", Ctrl: true}, KeyTypeCtrlA, ""},
+		{Key{Code: "space"}, KeyTypeSpace, " "},
+		{Key{Code: "tab", Shift: true}, KeyTypeShiftTab, ""},
+		{Key{Code: "left", Ctrl: true, Shift: true}, "ctrl+shift+left", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			e := NewKeydownEvent(tt.key)
+			if e.KeyType != tt.keyType || string(e.Runes) != tt.runes || e.Alt != tt.key.Alt {
+				t.Errorf("expected %q %q alt=%v, got %q %q alt=%v", tt.keyType, tt.runes, tt.key.Alt, e.KeyType, string(e.Runes), e.Alt)
+			}
+			if e.GetKey() != tt.key {
+				t.Errorf("expected key %+v, got %+v", tt.key, e.GetKey())
+			}
+		})
+	}
+
+	// events created by hand
+	if got := (&KeydownEvent{KeyType: KeyTypeShiftEnd}).GetKey(); got != (Key{Code: "end", Shift: true}) {
+		t.Errorf("expected shift+end, got %+v", got)
+	}
+	if got := (&KeydownEvent{Runes: []rune("b"), Alt: true}).GetKey(); got != (Key{Code: "b", Alt: true}) {
+		t.Errorf("expected alt+b, got %+v", got)
+	}
+}
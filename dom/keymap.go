@@ -0,0 +1,231 @@
+package dom
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// KeySequenceTimeout is how long a key sequence like "g g" waits for its
+// next key before it is dropped
+var KeySequenceTimeout = time.Second
+
+// now is time.Now, replaced by tests
+var now = time.Now
+
+// Binding binds a key, or a sequence of keys, to a handler
+type Binding struct {
+	// Keys is a key in the form ParseKey accepts, like "ctrl+s", or a
+	// sequence of them separated by spaces, like "g g"
+	Keys        string
+	Description string // Shown by KeyHelp, bindings without one are hidden
+	Handler     func()
+}
+
+// keySequence is the start of a sequence pressed so far
+type keySequence struct {
+	keys []string // canonical keys, see Key.String
+	at   time.Time
+}
+
+// parseKeySequence parses Binding.Keys into canonical keys
+func parseKeySequence(s string) ([]string, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid key sequence %q: no key", s)
+	}
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		key, err := ParseKey(field)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key.String()
+	}
+	return keys, nil
+}
+
+// scopedBinding is a binding with its parsed keys
+type scopedBinding struct {
+	Binding
+	keys []string
+}
+
+// keymapBindings returns the valid bindings of the keymaps from node up
+// to the root, innermost first
+func keymapBindings(node *Node) []scopedBinding {
+	var bindings []scopedBinding
+	for n := node; n != nil; n = n.Parent {
+		if n.Type != ElementTypeKeyMap {
+			continue
+		}
+		for _, b := range ExtractProps[KeyMapProps](n.Props).Bindings {
+			keys, err := parseKeySequence(b.Keys)
+			if err != nil || b.Handler == nil {
+				continue
+			}
+			bindings = append(bindings, scopedBinding{Binding: b, keys: keys})
+		}
+	}
+	return bindings
+}
+
+// hasPrefix reports whether keys starts with prefix
+func hasPrefix(keys, prefix []string) bool {
+	if len(prefix) > len(keys) {
+		return false
+	}
+	for i, key := range prefix {
+		if keys[i] != key {
+			return false
+		}
+	}
+	return true
+}
+
+// pendingKeys returns the sequence pressed so far, nil once it timed out
+func (d *DOM) pendingKeys() []string {
+	if len(d.keys.keys) == 0 || now().Sub(d.keys.at) > KeySequenceTimeout {
+		return nil
+	}
+	return d.keys.keys
+}
+
+// PendingKeys returns the start of a key sequence pressed so far, like
+// "g" while waiting for the second key of "g g", or "" if there is none
+func (d *DOM) PendingKeys() string {
+	return strings.Join(d.pendingKeys(), " ")
+}
+
+// runKeymap runs the binding of the keymaps around target matching the
+// key pressed after pending, the sequence pressed before, or remembers
+// the sequence if it starts a binding. The innermost keymap wins when
+// several bind the same keys. It reports whether the key was used.
+func (d *DOM) runKeymap(target *Node, event *DOMEvent, pending []string) bool {
+	key := event.KeydownEvent.GetKey()
+	bindings := keymapBindings(target)
+	if len(bindings) == 0 {
+		return false
+	}
+	for {
+		seq := append(append([]string(nil), pending...), key.String())
+		for _, b := range bindings {
+			if len(b.keys) == len(seq) && hasPrefix(b.keys, seq) {
+				b.Handler()
+				return true
+			}
+		}
+		for _, b := range bindings {
+			if hasPrefix(b.keys, seq) {
+				d.keys = keySequence{keys: seq, at: now()}
+				return true
+			}
+		}
+		if len(pending) == 0 {
+			return false
+		}
+		// the sequence broke, the key may start another one
+		pending = nil
+	}
+}
+
+// KeymapConflicts reports the bindings that cannot all work: keys that
+// do not parse, keys bound twice in a keymap, and keys starting a longer
+// sequence bound in the same keymap or one around it, which is then never
+// reached. Binding keys again in an inner keymap overrides them and is
+// no conflict.
+func (d *DOM) KeymapConflicts() []error {
+	var errs []error
+	var walk func(node *Node, outer []scopedBinding)
+	walk = func(node *Node, outer []scopedBinding) {
+		if node == nil {
+			return
+		}
+		if node.Type == ElementTypeKeyMap {
+			where := node.path
+			if where == "" {
+				where = "/"
+			}
+			var own []scopedBinding
+			seen := make(map[string]bool)
+			for _, b := range ExtractProps[KeyMapProps](node.Props).Bindings {
+				keys, err := parseKeySequence(b.Keys)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("keymap %s: %w", where, err))
+					continue
+				}
+				id := strings.Join(keys, " ")
+				if seen[id] {
+					errs = append(errs, fmt.Errorf("keymap %s: %q bound twice", where, id))
+					continue
+				}
+				seen[id] = true
+				own = append(own, scopedBinding{Binding: b, keys: keys})
+			}
+			for _, b := range own {
+				for _, other := range append(append([]scopedBinding(nil), own...), outer...) {
+					if len(b.keys) != len(other.keys) && hasPrefix(other.keys, b.keys) {
+						errs = append(errs, fmt.Errorf("keymap %s: %q hides %q", where, strings.Join(b.keys, " "), strings.Join(other.keys, " ")))
+					}
+				}
+				for _, other := range outer {
+					if len(b.keys) > len(other.keys) && hasPrefix(b.keys, other.keys) {
+						errs = append(errs, fmt.Errorf("keymap %s: %q is hidden by %q", where, strings.Join(b.keys, " "), strings.Join(other.keys, " ")))
+					}
+				}
+			}
+			outer = append(own, outer...)
+		}
+		for _, child := range node.Children {
+			walk(child, outer)
+		}
+	}
+	walk(d.Root, nil)
+	return errs
+}
+
+// updateKeyHelp fills the KeyHelp nodes with a line per binding active
+// around the focused node: the keys completing the pending sequence if
+// there is one, otherwise every binding with a description
+func (d *DOM) updateKeyHelp() {
+	if len(d.keyHelps) == 0 {
+		return
+	}
+	from := d.FocusedNode
+	if from == nil {
+		from = d.Root
+	}
+	pending := d.pendingKeys()
+	type line struct{ keys, description string }
+	var lines []line
+	seen := make(map[string]bool)
+	width := 0
+	for _, b := range keymapBindings(from) {
+		id := strings.Join(b.keys, " ")
+		if seen[id] {
+			// overridden by an inner keymap
+			continue
+		}
+		seen[id] = true
+		if b.Description == "" || len(b.keys) == len(pending) || !hasPrefix(b.keys, pending) {
+			continue
+		}
+		keys := strings.Join(b.keys[len(pending):], " ")
+		width = max(width, utf8.RuneCountInString(keys))
+		lines = append(lines, line{keys, b.Description})
+	}
+
+	for _, help := range d.keyHelps {
+		help.Children = nil
+		if len(pending) == 0 && ExtractProps[KeyHelpProps](help.Props).PendingOnly {
+			continue
+		}
+		for i, l := range lines {
+			pad := strings.Repeat(" ", width-utf8.RuneCountInString(l.keys))
+			child := Div(DivProps{}, Text(l.keys+pad+"  "+l.description))
+			help.Children = append(help.Children, child)
+			d.setupVNode(child, help, help.Window, ChildPath(help.path, i, child))
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package dom
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// keymapApp renders a list and an input in keymaps, recording the
+// bindings run, and renders again after every key like CharmApp does
+type keymapApp struct {
+	calls   []string
+	focused string // "list" or "input"
+	input   inputApp
+	dom     *DOM
+}
+
+func (a *keymapApp) bind(keys, description string) Binding {
+	return Binding{Keys: keys, Description: description, Handler: func() { a.calls = append(a.calls, description) }}
+}
+
+func (a *keymapApp) render() {
+	list := KeyMap(KeyMapProps{Bindings: []Binding{
+		a.bind("g g", "list top"),
+		a.bind("d", "delete"),
+	}}, Div(DivProps{Focusable: true, Focused: a.focused == "list"}))
+	input := Input(InputProps{
+		Value:           a.input.value,
+		CursorPosition:  a.input.cursor,
+		SelectionAnchor: a.input.anchor,
+		Focused:         a.focused == "input",
+		OnChange:        func(s string) { a.input.value = s },
+		OnCursorMove:    func(p int) { a.input.cursor = p },
+		OnSelect:        func(p *int) { a.input.anchor = p },
+	})
+	root := KeyMap(KeyMapProps{Bindings: []Binding{
+		a.bind("g g", "top"),
+		a.bind("g e", "bottom"),
+		a.bind("ctrl+c ctrl+c", "quit"),
+		a.bind("up", "up"),
+	}}, Div(DivProps{}, list, input, KeyHelp(KeyHelpProps{})))
+
+	prev := a.dom
+	a.dom = NewDOM(root, &Window{})
+	a.dom.Inherit(prev)
+}
+
+func (a *keymapApp) press(keys ...string) {
+	for _, s := range keys {
+		key, err := ParseKey(s)
+		if err != nil {
+			panic(err)
+		}
+		a.dom.DispatchKeyDownEvent(NewKeydownEvent(key))
+		a.render()
+	}
+}
+
+// help returns the lines of the KeyHelp
+func (a *keymapApp) help() []string {
+	var lines []string
+	for _, child := range a.dom.keyHelps[0].Children {
+		lines = append(lines, child.Children[0].Text)
+	}
+	return lines
+}
+
+func TestKeymapSequences(t *testing.T) {
+	clock := time.Unix(0, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		name     string
+		focused  string
+		keys     []string
+		wait     bool // the sequence times out before the last key
+		expected []string
+	}{
+		{name: "Sequence", keys: []string{"g", "e"}, expected: []string{"bottom"}},
+		{name: "InnerKeymapWins", focused: "list", keys: []string{"g", "g"}, expected: []string{"list top"}},
+		{name: "OuterKeymapAround", focused: "list", keys: []string{"g", "e", "d"}, expected: []string{"bottom", "delete"}},
+		{name: "BrokenSequenceRestarts", keys: []string{"g", "ctrl+c", "ctrl+c"}, expected: []string{"quit"}},
+		{name: "Timeout", keys: []string{"g", "g"}, wait: true, expected: nil},
+		{name: "TextFieldKeepsText", focused: "input", keys: []string{"g", "g"}, expected: nil},
+		{name: "TextFieldLeavesOtherKeys", focused: "input", keys: []string{"ctrl+c", "ctrl+c", "up"}, expected: []string{"quit", "up"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &keymapApp{focused: tt.focused}
+			app.render()
+			app.press(tt.keys[:len(tt.keys)-1]...)
+			if tt.wait {
+				clock = clock.Add(KeySequenceTimeout + time.Millisecond)
+			}
+			app.press(tt.keys[len(tt.keys)-1])
+			if !reflect.DeepEqual(app.calls, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, app.calls)
+			}
+		})
+	}
+}
+
+func TestKeymapLeavesCopyToTextField(t *testing.T) {
+	anchor := 0
+	app := &keymapApp{focused: "input", input: inputApp{value: "hello", cursor: 5, anchor: &anchor}}
+	app.render()
+	app.dom.Clipboard = &MemoryClipboard{}
+	app.press("ctrl+c", "ctrl+c")
+	if len(app.calls) != 0 {
+		t.Errorf("expected ctrl+c to copy the selection, got bindings %v", app.calls)
+	}
+	if text, _ := app.dom.Clipboard.ReadText(); text != "hello" {
+		t.Errorf("expected %q copied, got %q", "hello", text)
+	}
+}
+
+func TestKeyHelp(t *testing.T) {
+	app := &keymapApp{focused: "list"}
+	app.render()
+	expected := []string{
+		"g g            list top",
+		"d              delete",
+		"g e            bottom",
+		"ctrl+c ctrl+c  quit",
+		"up             up",
+	}
+	if got := app.help(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected help\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	app.press("g")
+	if got := app.dom.PendingKeys(); got != "g" {
+		t.Errorf("expected %q pending, got %q", "g", got)
+	}
+	expected = []string{"g  list top", "e  bottom"}
+	if got := app.help(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected help while pending\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestKeymapConflicts(t *testing.T) {
+	nop := func() {}
+	root := KeyMap(KeyMapProps{Bindings: []Binding{
+		{Keys: "g", Handler: nop},
+		{Keys: "q q", Handler: nop},
+		{Keys: "ctrl+s", Handler: nop},
+		{Keys: "ctrl+s", Handler: nop},
+		{Keys: "hyper+x", Handler: nop},
+	}}, KeyMap(KeyMapProps{Bindings: []Binding{
+		{Keys: "g g", Handler: nop},
+		{Keys: "q", Handler: nop},
+		{Keys: "ctrl+s", Handler: nop}, // overrides
+	}}))
+	d := NewDOM(root, &Window{})
+
+	var got []string
+	for _, err := range d.KeymapConflicts() {
+		got = append(got, err.Error())
+	}
+	expected := []string{
+		`keymap /: "ctrl+s" bound twice`,
+		`keymap /: invalid key "hyper+x": unknown modifier "hyper"`,
+		`keymap /keymap:0: "g g" is hidden by "g"`,
+		`keymap /keymap:0: "q" hides "q q"`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected conflicts\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
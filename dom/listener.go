@@ -0,0 +1,70 @@
+package dom
+
+// ListenerID identifies a listener added by AddEventListener
+type ListenerID int
+
+// EventListeners are handlers of the whole DOM rather than of a node,
+// for global shortcuts: capture listeners run before any node sees an
+// event, the others after it bubbled up to the root.
+// The zero value is empty.
+type EventListeners struct {
+	listeners []eventListener
+	lastID    ListenerID
+}
+
+type eventListener struct {
+	id        ListenerID
+	eventType EventType
+	handler   EventHandler
+	capture   bool
+}
+
+// Add adds a listener for eventType, in the capture phase if capture is set
+func (l *EventListeners) Add(eventType EventType, handler EventHandler, capture bool) ListenerID {
+	l.lastID++
+	l.listeners = append(l.listeners, eventListener{id: l.lastID, eventType: eventType, handler: handler, capture: capture})
+	return l.lastID
+}
+
+// Remove removes the listener with the given id, if still there
+func (l *EventListeners) Remove(id ListenerID) {
+	for i, listener := range l.listeners {
+		if listener.id == id {
+			l.listeners = append(l.listeners[:i:i], l.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// run calls the listeners of the phase for event in the order they were added
+func (l *EventListeners) run(event *DOMEvent, capture bool) {
+	if l == nil {
+		return
+	}
+	// listeners added or removed by a listener apply to the next event
+	for _, listener := range append([]eventListener(nil), l.listeners...) {
+		if event.ImmediatePropagationStopped {
+			return
+		}
+		if listener.eventType == event.Type && listener.capture == capture {
+			listener.handler(event)
+		}
+	}
+}
+
+// AddEventListener adds a handler of the whole DOM for eventType, in the
+// capture phase if capture is set, see EventListeners.
+// Listeners survive re-renders through Inherit.
+func (d *DOM) AddEventListener(eventType EventType, handler EventHandler, capture bool) ListenerID {
+	if d.Listeners == nil {
+		d.Listeners = &EventListeners{}
+	}
+	return d.Listeners.Add(eventType, handler, capture)
+}
+
+// RemoveEventListener removes a listener added by AddEventListener
+func (d *DOM) RemoveEventListener(id ListenerID) {
+	if d.Listeners != nil {
+		d.Listeners.Remove(id)
+	}
+}
@@ -0,0 +1,204 @@
+package dom
+
+import (
+	"strings"
+
+	"github.com/xhd2015/go-dom-tui/log"
+)
+
+// MouseButton identifies the button involved in a mouse event
+type MouseButton string
+
+const (
+	MouseButtonNone   MouseButton = ""
+	MouseButtonLeft   MouseButton = "left"
+	MouseButtonMiddle MouseButton = "middle"
+	MouseButtonRight  MouseButton = "right"
+)
+
+// MouseAction is the raw action reported by the terminal
+type MouseAction string
+
+const (
+	MouseActionPress   MouseAction = "press"
+	MouseActionRelease MouseAction = "release"
+	MouseActionMotion  MouseAction = "motion"
+	MouseActionWheel   MouseAction = "wheel"
+)
+
+// MouseEvent represents a mouse event in terminal cell coordinates,
+// (0, 0) being the top-left cell of the rendered screen
+type MouseEvent struct {
+	X      int
+	Y      int
+	Action MouseAction
+	Button MouseButton
+
+	// DeltaX and DeltaY are the scroll amounts of a wheel event in lines,
+	// positive DeltaY scrolls down and positive DeltaX scrolls right
+	DeltaX int
+	DeltaY int
+
+	Alt   bool
+	Ctrl  bool
+	Shift bool
+}
+
+// HitTester maps screen coordinates to nodes
+type HitTester interface {
+	// NodeAt returns the deepest node rendered at cell (x, y), or nil
+	NodeAt(x, y int) *Node
+}
+
+// mouseState is the mouse interaction state carried across re-renders by Inherit
+type mouseState struct {
+	pressed      bool
+	pressedPath  string
+	hoveredPaths []string // from the root down to the deepest hovered node
+}
+
+// DispatchMouseEvent hit-tests the mouse event against HitTester and dispatches
+// DOM events to the deepest node under the cursor, bubbling like key events:
+//   - press: mousedown, focusing the nearest focusable ancestor by default
+//   - release: mouseup, then click if released over the node that was pressed
+//   - motion: mouseenter/mouseleave for nodes entered or left, then mousemove
+//   - wheel: wheel
+func (d *DOM) DispatchMouseEvent(mouseEvent *MouseEvent) {
+	if d.Root == nil {
+		return
+	}
+	target := d.hitTest(mouseEvent.X, mouseEvent.Y)
+
+	log.Logf("DOM: DispatchMouseEvent action='%s' button='%s' at (%d,%d) to node %s", mouseEvent.Action, mouseEvent.Button, mouseEvent.X, mouseEvent.Y, target.Type)
+
+	switch mouseEvent.Action {
+	case MouseActionPress:
+		d.updateHover(target, mouseEvent)
+		d.mouse.pressed = true
+		d.mouse.pressedPath = target.path
+		event := d.dispatchMouse(EventTypeMouseDown, target, mouseEvent)
+		if !event.DefaultPrevented {
+			d.handleMouseDefault(target, event)
+		}
+	case MouseActionRelease:
+		d.dispatchMouse(EventTypeMouseUp, target, mouseEvent)
+		if d.mouse.pressed && d.mouse.pressedPath == target.path {
+			d.dispatchMouse(EventTypeClick, target, mouseEvent)
+		}
+		d.mouse.pressed = false
+	case MouseActionMotion:
+		d.updateHover(target, mouseEvent)
+		d.dispatchMouse(EventTypeMouseMove, target, mouseEvent)
+	case MouseActionWheel:
+		event := d.dispatchMouse(EventTypeWheel, target, mouseEvent)
+		if !event.DefaultPrevented {
+			d.handleMouseDefault(target, event)
+		}
+	}
+}
+
+func (d *DOM) hitTest(x, y int) *Node {
+	if d.HitTester != nil {
+		if node := d.HitTester.NodeAt(x, y); node != nil {
+			return node
+		}
+	}
+	return d.Root
+}
+
+// dispatchMouse creates a mouse event targeting node and dispatches it
+func (d *DOM) dispatchMouse(eventType EventType, node *Node, mouseEvent *MouseEvent) *DOMEvent {
+	event := &DOMEvent{
+		Type:          eventType,
+		Target:        node,
+		CurrentTarget: node,
+		MouseEvent:    mouseEvent,
+	}
+	d.dispatch(node, event)
+	return event
+}
+
+// updateHover dispatches mouseleave to nodes no longer under the cursor,
+// deepest first, then mouseenter to newly hovered nodes, outermost first.
+// Neither event bubbles.
+func (d *DOM) updateHover(target *Node, mouseEvent *MouseEvent) {
+	var chain []*Node
+	for n := target; n != nil; n = n.Parent {
+		chain = append(chain, n)
+	}
+	newPaths := make([]string, len(chain))
+	for i, n := range chain {
+		newPaths[len(chain)-1-i] = n.path
+	}
+
+	oldPaths := d.mouse.hoveredPaths
+	common := 0
+	for common < len(oldPaths) && common < len(newPaths) && oldPaths[common] == newPaths[common] {
+		common++
+	}
+
+	for i := len(oldPaths) - 1; i >= common; i-- {
+		if node := d.Root.findByPath(oldPaths[i]); node != nil {
+			d.dispatchDirect(EventTypeMouseLeave, node, mouseEvent)
+		}
+	}
+	for i := common; i < len(newPaths); i++ {
+		d.dispatchDirect(EventTypeMouseEnter, chain[len(chain)-1-i], mouseEvent)
+	}
+	d.mouse.hoveredPaths = newPaths
+}
+
+// dispatchDirect delivers a non-bubbling event to node only
+func (d *DOM) dispatchDirect(eventType EventType, node *Node, mouseEvent *MouseEvent) {
+	handler := node.GetEventHandler(eventType)
+	if handler == nil {
+		return
+	}
+	handler(&DOMEvent{
+		Type:          eventType,
+		Target:        node,
+		CurrentTarget: node,
+		MouseEvent:    mouseEvent,
+	})
+}
+
+func (d *DOM) handleMouseDefault(node *Node, event *DOMEvent) {
+	switch event.Type {
+	case EventTypeMouseDown:
+		// focus the nearest focusable ancestor, like a browser does,
+		// unless a focus scope traps the focus elsewhere
+		for n := node; n != nil; n = n.Parent {
+			if n.IsFocusable() {
+				if d.canFocus(n) {
+					d.SetFocus(n)
+				}
+				return
+			}
+		}
+	case EventTypeWheel:
+		// scroll the nearest scroll view that can still scroll that way
+		for view := node.closestScrollView(); view != nil; view = view.Parent.closestScrollView() {
+			props := ExtractProps[ScrollViewProps](view.Props)
+			extent, _ := d.scrollExtent(view, props)
+			if d.ScrollTo(view, extent.Offset+event.MouseEvent.DeltaY) {
+				return
+			}
+		}
+	}
+}
+
+// findByPath finds the node with the given path in the subtree
+func (c *Node) findByPath(path string) *Node {
+	if c.path == path {
+		return c
+	}
+	for _, child := range c.Children {
+		if child == nil {
+			continue
+		}
+		if path == child.path || strings.HasPrefix(path, child.path+"/") {
+			return child.findByPath(path)
+		}
+	}
+	return nil
+}
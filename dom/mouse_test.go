@@ -0,0 +1,121 @@
+package dom
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fixedHitTester resolves every coordinate to the same node
+type fixedHitTester struct {
+	node *Node
+}
+
+func (h *fixedHitTester) NodeAt(x, y int) *Node {
+	return h.node
+}
+
+func TestDispatchMouseEventClickBubbles(t *testing.T) {
+	var calls []string
+	button := Button(ButtonProps{OnClick: func() {
+		calls = append(calls, "button click")
+	}}, Text("OK"))
+	root := Div(DivProps{
+		OnMouseDown: func(e *DOMEvent) {
+			calls = append(calls, "div mousedown")
+		},
+		OnClick: func(e *DOMEvent) {
+			calls = append(calls, "div click")
+		},
+	}, button)
+
+	d := NewDOM(root, &Window{})
+	d.HitTester = &fixedHitTester{node: button}
+
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionPress, Button: MouseButtonLeft})
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionRelease, Button: MouseButtonLeft})
+
+	expected := []string{"div mousedown", "button click", "div click"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestDispatchMouseEventNoClickWhenReleasedElsewhere(t *testing.T) {
+	clicks := 0
+	a := Div(DivProps{OnClick: func(e *DOMEvent) { clicks++ }})
+	b := Div(DivProps{OnClick: func(e *DOMEvent) { clicks++ }})
+	root := Div(DivProps{}, a, b)
+
+	d := NewDOM(root, &Window{})
+	hit := &fixedHitTester{node: a}
+	d.HitTester = hit
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionPress, Button: MouseButtonLeft})
+	hit.node = b
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionRelease, Button: MouseButtonLeft})
+
+	if clicks != 0 {
+		t.Errorf("expected no click, got %d", clicks)
+	}
+}
+
+func TestDispatchMouseEventClickSurvivesRerender(t *testing.T) {
+	clicks := 0
+	render := func() *Node {
+		return Div(DivProps{}, Div(DivProps{OnClick: func(e *DOMEvent) { clicks++ }}))
+	}
+
+	first := render()
+	d := NewDOM(first, &Window{})
+	d.HitTester = &fixedHitTester{node: first.Children[0]}
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionPress, Button: MouseButtonLeft})
+
+	second := render()
+	next := NewDOM(second, &Window{})
+	next.Inherit(d)
+	next.HitTester = &fixedHitTester{node: second.Children[0]}
+	next.DispatchMouseEvent(&MouseEvent{Action: MouseActionRelease, Button: MouseButtonLeft})
+
+	if clicks != 1 {
+		t.Errorf("expected 1 click, got %d", clicks)
+	}
+}
+
+func TestDispatchMouseEventHover(t *testing.T) {
+	var calls []string
+	item := func(name string) *Node {
+		return Li(ListItemProps{
+			OnMouseEnter: func(e *DOMEvent) { calls = append(calls, "enter "+name) },
+			OnMouseLeave: func(e *DOMEvent) { calls = append(calls, "leave "+name) },
+		}, Text(name))
+	}
+	a := item("a")
+	b := item("b")
+	root := Ul(DivProps{OnMouseEnter: func(e *DOMEvent) { calls = append(calls, "enter ul") }}, a, b)
+
+	d := NewDOM(root, &Window{})
+	hit := &fixedHitTester{node: a}
+	d.HitTester = hit
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionMotion})
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionMotion})
+	hit.node = b
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionMotion})
+
+	expected := []string{"enter ul", "enter a", "leave a", "enter b"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestDispatchMouseEventFocusesOnMouseDown(t *testing.T) {
+	focused := false
+	input := Input(InputProps{OnFocus: func() { focused = true }})
+	root := Div(DivProps{}, Div(DivProps{}, input))
+
+	d := NewDOM(root, &Window{})
+	d.HitTester = &fixedHitTester{node: input}
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionPress, Button: MouseButtonLeft})
+
+	if !focused {
+		t.Errorf("expected input to be focused on mousedown")
+	}
+}
@@ -1,5 +1,18 @@
 package dom
 
+// eventHandlerProps maps event types to the camel-cased prop holding their handler
+var eventHandlerProps = map[EventType]string{
+	EventTypeKeydown:    "onKeyDown",
+	EventTypeResize:     "onWindowResize",
+	EventTypeMouseDown:  "onMouseDown",
+	EventTypeMouseUp:    "onMouseUp",
+	EventTypeClick:      "onClick",
+	EventTypeWheel:      "onWheel",
+	EventTypeMouseMove:  "onMouseMove",
+	EventTypeMouseEnter: "onMouseEnter",
+	EventTypeMouseLeave: "onMouseLeave",
+}
+
 func (c *Node) GetEventHandler(eventType EventType) EventHandler {
 	if c.Props == nil {
 		return nil
@@ -9,14 +22,8 @@ func (c *Node) GetEventHandler(eventType EventType) EventHandler {
 	if h != nil {
 		return h
 	}
-	if eventType == "keydown" {
-		h := getPropHandler(c.Props, "onKeyDown")
-		if h != nil {
-			return h
-		}
-	}
-	if eventType == "resize" {
-		h := getPropHandler(c.Props, "onWindowResize")
+	if propName, ok := eventHandlerProps[eventType]; ok {
+		h := getPropHandler(c.Props, propName)
 		if h != nil {
 			return h
 		}
@@ -24,6 +31,19 @@ func (c *Node) GetEventHandler(eventType EventType) EventHandler {
 	return nil
 }
 
+// GetCaptureEventHandler returns the handler of the node for the capture
+// phase of eventType, held by the handler prop name followed by "Capture",
+// like onKeyDownCapture
+func (c *Node) GetCaptureEventHandler(eventType EventType) EventHandler {
+	if c.Props == nil {
+		return nil
+	}
+	if propName, ok := eventHandlerProps[eventType]; ok {
+		return getPropHandler(c.Props, propName+"Capture")
+	}
+	return getPropHandler(c.Props, "on"+string(eventType)+"Capture")
+}
+
 func getPropHandler(props Props, key string) EventHandler {
 	handler, ok := props.Get(key)
 	if !ok {
@@ -35,10 +55,11 @@ func getPropHandler(props Props, key string) EventHandler {
 		return fn
 	}
 
-	fnNoRes, ok := handler.(func(event *DOMEvent))
-	if ok && fnNoRes != nil {
+	// handlers like ButtonProps.OnClick don't care about the event
+	fnNoArg, ok := handler.(func())
+	if ok && fnNoArg != nil {
 		return func(event *DOMEvent) {
-			fnNoRes(event)
+			fnNoArg()
 		}
 	}
 
@@ -16,6 +16,35 @@ const (
 	AlignLeft   Align = "left"
 	AlignRight  Align = "right"
 	AlignCenter Align = "center"
+
+	// cross axis alignment of flex items, see DivProps.AlignItems
+	AlignStart   Align = "start"
+	AlignEnd     Align = "end"
+	AlignStretch Align = "stretch"
+)
+
+// Justify distributes the free space of a flex container along its main axis
+type Justify string
+
+const (
+	JustifyStart        Justify = "start" // default
+	JustifyEnd          Justify = "end"
+	JustifyCenter       Justify = "center"
+	JustifySpaceBetween Justify = "space-between"
+	JustifySpaceAround  Justify = "space-around"
+	JustifySpaceEvenly  Justify = "space-evenly"
+)
+
+// TextOverflow is how text wider than the space it is given is laid out
+type TextOverflow string
+
+const (
+	TextWrap           TextOverflow = "wrap"            // break lines between words, and inside words longer than a line
+	TextWrapHard       TextOverflow = "wrap-hard"       // break lines at the width, inside words
+	TextEllipsis       TextOverflow = "ellipsis"        // cut the end of each line, marking it with "…"
+	TextEllipsisStart  TextOverflow = "ellipsis-start"  // cut the start of each line
+	TextEllipsisMiddle TextOverflow = "ellipsis-middle" // cut the middle of each line
+	TextClip           TextOverflow = "clip"            // default: cut the end of each line
 )
 
 func ExtractProps[T any](props Props) T {
@@ -127,11 +156,16 @@ type TextNodeProps struct {
 	Style     styles.Style
 	Focused   bool
 	Focusable bool
+	TabIndex  *int // Optional: place in the tab order, see DOM.TabOrder
+
+	TextOverflow TextOverflow // How lines wider than the space given are laid out
 
 	OnFocus func()
 	OnBlur  func()
 
-	OnKeyDown func(*DOMEvent)
+	OnKeyDown        func(*DOMEvent)
+	OnKeyDownCapture func(*DOMEvent)
+	OnClick          func(*DOMEvent)
 }
 
 // ButtonProps represents props for button elements
@@ -145,19 +179,138 @@ type ButtonProps struct {
 
 // DivProps represents props for div elements
 type DivProps struct {
-	Style styles.Style
-	Width int   // Container width in characters (0 = use window width)
-	Align Align // Vertical alignment for HDiv: "top" (default) or "bottom"
+	Style  styles.Style
+	Width  int   // Container width in characters (0 = fit content, or the width given when a child grows, see below)
+	Height int   // Container height in lines (0 = fit content)
+	Align  Align // Vertical alignment for HDiv: "top" (default) or "bottom"
+
+	TextOverflow TextOverflow // Span, P, H1 and H2: how lines wider than the space given are laid out
+
+	// Flex container: Div lays its children out top to bottom, HDiv left to right.
+	// The container takes all the space it is given when a child grows or
+	// when JustifyContent is set, otherwise it fits its children. The space
+	// given to the root is the window, so a Width of 0 no longer means the
+	// window width for nested containers: they get the width of their parent.
+	JustifyContent Justify // distribution of free space along the main axis
+	AlignItems     Align   // "start" (default), "center", "end" or "stretch"; overrides Align
+	Gap            int     // cells between adjacent children
+
+	// SpatialNavigation makes the arrows move the focus to the nearest
+	// focusable node on screen in their direction, among the nodes inside,
+	// for grids and button bars. Up and down fall back to the document
+	// order when there is no node that way.
+	SpatialNavigation bool
+
+	// Flex item: how the div is sized by a Div or HDiv parent.
+	// Without a FlexBasis the div starts at the size of its content,
+	// and does not shrink below it unless MinWidth or MinHeight allow it.
+	FlexGrow   int  // share of the free space the div takes
+	FlexShrink *int // share of the missing space the div gives up, 1 if nil
+	FlexBasis  Size // initial size along the main axis
+	MinWidth   Size
+	MaxWidth   Size
+	MinHeight  Size
+	MaxHeight  Size
+
+	Position Position // Placement when the div is a child of a ZDiv
+
+	OnKeyDown        func(*DOMEvent)
+	OnKeyDownCapture func(*DOMEvent) // runs in the capture phase, before the handlers of the nodes inside
+	OnWindowResize   func(*DOMEvent)
+
+	// mouse handlers, see MouseEvent
+	OnMouseDown  func(*DOMEvent)
+	OnMouseUp    func(*DOMEvent)
+	OnClick      func(*DOMEvent)
+	OnWheel      func(*DOMEvent)
+	OnMouseMove  func(*DOMEvent)
+	OnMouseEnter func(*DOMEvent) // does not bubble
+	OnMouseLeave func(*DOMEvent) // does not bubble
+
+	// capture phase mouse handlers, run before the ones of the nodes inside
+	OnMouseDownCapture func(*DOMEvent)
+	OnMouseUpCapture   func(*DOMEvent)
+	OnClickCapture     func(*DOMEvent)
+	OnWheelCapture     func(*DOMEvent)
+	OnMouseMoveCapture func(*DOMEvent)
 
-	OnKeyDown      func(*DOMEvent)
-	OnWindowResize func(*DOMEvent)
+	Focused   bool
+	Focusable bool
+	TabIndex  *int // Optional: place in the tab order, see DOM.TabOrder
+	OnFocus   func()
+	OnBlur    func()
+}
+
+// ScrollViewProps represents props for scrollview elements
+// A scroll view takes all the width it is given, and in a Div the height
+// its siblings leave unless Height is set.
+type ScrollViewProps struct {
+	Style  styles.Style
+	Height int // Visible lines (0 = the space given by the parent)
+
+	// ScrollOffset is the first line of the content in view. Like the value
+	// of an input it is owned by the app: scrolling with the keyboard or the
+	// mouse wheel calls OnScroll with the new offset, clamped to the content.
+	ScrollOffset int
+	OnScroll     func(offset int)
+
+	// ItemHeight is the height of every child in lines. When set, only the
+	// children in view are rendered, which keeps long lists cheap.
+	// When 0, every child is rendered on every frame to measure the content,
+	// and scrolling is clamped to the content height last measured, so large
+	// lists need an ItemHeight. Otherwise, mark their children with Memo:
+	// measuring them then reuses their output of the last frame.
+	ItemHeight int
+
+	Scrollbar bool // Draw a scrollbar in the rightmost column
+
+	OnKeyDown        func(*DOMEvent)
+	OnKeyDownCapture func(*DOMEvent)
+	OnWheel          func(*DOMEvent)
 
 	Focused   bool
 	Focusable bool
+	TabIndex  *int // Optional: place in the tab order, see DOM.TabOrder
 	OnFocus   func()
 	OnBlur    func()
 }
 
+// FocusScopeProps represents props for focusscope elements
+// A scope with Trap is modal: while the focus is inside it, or outside every
+// trapping scope of the tree when it is the last one, Tab, Shift-Tab, the
+// arrows and clicks only move the focus to nodes of the scope. The scope
+// lays its children out like a Fragment.
+// Calling DOM.UpdateFocusScopes after each render, as CharmApp does, makes
+// the scope give the focus back to the node focused before it mounted
+// when it unmounts.
+type FocusScopeProps struct {
+	Trap      bool     // Keep the focus inside the scope, like a modal dialog
+	AutoFocus bool     // Focus the first node of the scope in tab order when it mounts
+	Position  Position // Placement when the scope is a child of a ZDiv
+}
+
+// KeyMapProps represents props for keymap elements
+// The bindings work while the focus is inside the keymap, or everywhere
+// for a keymap around the root. Keys go to the handlers of the nodes and
+// the listeners of the DOM first, any of which can stop them with
+// StopPropagation or PreventDefault, and to the focused text field, which
+// keeps the keys it edits with. A key matching a binding then runs it
+// instead of moving the focus or scrolling.
+// The keymap lays its children out like a Fragment.
+type KeyMapProps struct {
+	Bindings []Binding
+	Position Position // Placement when the keymap is a child of a ZDiv
+}
+
+// KeyHelpProps represents props for keyhelp elements
+// The DOM fills the help with a line per binding with a description of
+// the keymaps around the focused node, inner ones first, or the keys
+// completing a sequence while one is pending.
+type KeyHelpProps struct {
+	PendingOnly bool     // Only show while a sequence is pending, like a hint for "ctrl+c ctrl+c"
+	Position    Position // Placement when the help is a child of a ZDiv
+}
+
 // CounterProps represents props for Counter component
 type CounterProps struct {
 	InitialValue int
@@ -205,38 +358,85 @@ type InputProps struct {
 
 	Width int // Input width in characters (0 = use window width)
 
-	CursorPosition int // Cursor position
-	OnCursorMove   func(position int)
+	CursorPosition  int  // Cursor position
+	SelectionAnchor *int // Other end of the selection, nil when nothing is selected
+	OnCursorMove    func(position int)
+	OnSelect        func(anchor *int)
 
-	OnKeyDown func(e *DOMEvent) // Key down callback
-	OnChange  func(string)      // Value change callback
-	OnFocus   func()            // Focus callback
-	OnBlur    func()            // Blur callback
+	OnKeyDown        func(e *DOMEvent) // Key down callback
+	OnKeyDownCapture func(e *DOMEvent) // Key down callback of the capture phase
+	OnChange         func(string)      // Value change callback
+	OnFocus          func()            // Focus callback
+	OnBlur           func()            // Blur callback
 
 	Focused bool // Whether the input is focused
 
 	Focusable *bool // Optional: nil = default (true for input), true/false = explicit
+	TabIndex  *int  // Optional: place in the tab order, see DOM.TabOrder
+}
+
+// TextAreaProps represents props for textarea elements
+// Like an input, the value, the cursor and the selection are owned by the
+// app: key presses call OnChange, OnCursorMove and OnSelect, see EditText.
+type TextAreaProps struct {
+	Style       styles.Style
+	Placeholder string
+	Value       string // Lines are separated by "\n"
+
+	Width  int // Width in characters (0 = the width given by the parent)
+	Height int // Visible lines (0 = fit the content), the view follows the cursor
+
+	CursorPosition  int  // Cursor as a rune offset into Value
+	SelectionAnchor *int // Other end of the selection, nil when nothing is selected
+	OnCursorMove    func(position int)
+	OnSelect        func(anchor *int)
+
+	MaxLength   int          // Maximum length in runes (0 = unlimited)
+	NoWrap      bool         // Cut long lines instead of wrapping them, the view follows the cursor
+	LineNumbers bool         // Show line numbers in a gutter on the left
+	History     *TextHistory // Enables undo (Ctrl-Z) and redo (Ctrl-Y)
+
+	OnKeyDown        func(e *DOMEvent)
+	OnKeyDownCapture func(e *DOMEvent)
+	OnChange         func(string)
+	OnFocus          func()
+	OnBlur           func()
+
+	Focused   bool
+	Focusable *bool // Optional: nil = default (true), true/false = explicit
+	TabIndex  *int  // Optional: place in the tab order, see DOM.TabOrder
 }
 
 // Focusable creates a boolean pointer for focusable property
 func Focusable(value bool) *bool {
 	return &value
 }
+
+// TabIndex creates an int pointer for the tabIndex property
+func TabIndex(value int) *int {
+	return &value
+}
 func String(value string) *string {
 	return &value
 }
 
 // ListItemProps represents props for focusable li elements
 type ListItemProps struct {
-	Style      styles.Style
-	Index      int
-	Selected   bool
-	ItemPrefix *string
-	Focused    bool
-	OnFocus    func()
-	OnBlur     func()
-	OnKeyDown  func(e *DOMEvent)
-	Focusable  *bool
+	Style            styles.Style
+	Index            int
+	Selected         bool
+	ItemPrefix       *string
+	Focused          bool
+	OnFocus          func()
+	OnBlur           func()
+	OnKeyDown        func(e *DOMEvent)
+	OnKeyDownCapture func(e *DOMEvent)
+	Focusable        *bool
+	TabIndex         *int
+
+	OnClick      func(e *DOMEvent)
+	OnMouseEnter func(e *DOMEvent) // does not bubble
+	OnMouseLeave func(e *DOMEvent) // does not bubble
 }
 
 // SpacerProps represents props for spacer elements
@@ -0,0 +1,152 @@
+package dom
+
+import "math"
+
+// ScrollExtent is the geometry of a rendered scroll view
+type ScrollExtent struct {
+	Viewport      Rect // area of the screen the content is shown in
+	Offset        int  // first line of the content in view
+	ContentHeight int  // height of the whole content
+}
+
+// ScrollMeasurer reports where scroll views and their children were rendered.
+// The layout of the last render implements it alongside HitTester, without
+// it scrolling relies on the Height and ItemHeight props.
+type ScrollMeasurer interface {
+	ScrollExtentOf(node *Node) (ScrollExtent, bool)
+	BoundsMeasurer
+}
+
+// scrollExtent returns the geometry of view from the last render, or as
+// far as its props and the last time it was measured tell.
+// ok is false if the content height is unknown.
+func (d *DOM) scrollExtent(view *Node, props ScrollViewProps) (extent ScrollExtent, ok bool) {
+	if m, isMeasurer := d.HitTester.(ScrollMeasurer); isMeasurer {
+		if extent, ok := m.ScrollExtentOf(view); ok {
+			if d.scrollExtents == nil {
+				d.scrollExtents = make(map[string]ScrollExtent)
+			}
+			d.scrollExtents[view.path] = extent
+			return extent, true
+		}
+	}
+	extent = ScrollExtent{
+		Viewport: Rect{Height: props.Height},
+		Offset:   props.ScrollOffset,
+	}
+	if props.ItemHeight > 0 {
+		extent.ContentHeight = len(view.Children) * props.ItemHeight
+		return extent, true
+	}
+	measured, ok := d.scrollExtents[view.path]
+	if !ok {
+		return extent, false
+	}
+	extent.ContentHeight = measured.ContentHeight
+	if extent.Viewport.Height == 0 {
+		extent.Viewport = measured.Viewport
+	}
+	return extent, true
+}
+
+// ScrollTo scrolls view, a scroll view, so that its content starts at
+// line offset, clamped to the content. It reports whether OnScroll was called.
+func (d *DOM) ScrollTo(view *Node, offset int) bool {
+	sp, ok := view.Props.(StructProps[ScrollViewProps])
+	if !ok {
+		return false
+	}
+	props := sp.Value
+	if extent, ok := d.scrollExtent(view, props); ok {
+		offset = min(offset, extent.ContentHeight-extent.Viewport.Height)
+	}
+	offset = max(offset, 0)
+	if offset == props.ScrollOffset || props.OnScroll == nil {
+		return false
+	}
+	props.OnScroll(offset)
+	return true
+}
+
+// scrollByKey scrolls view by a line, a page, or to either end
+func (d *DOM) scrollByKey(view *Node, key KeyType) bool {
+	props := ExtractProps[ScrollViewProps](view.Props)
+	extent, known := d.scrollExtent(view, props)
+	page := max(extent.Viewport.Height, 1)
+
+	offset := extent.Offset
+	switch key {
+	case KeyTypeUp:
+		offset--
+	case KeyTypeDown:
+		offset++
+	case KeyTypePgUp:
+		offset -= page
+	case KeyTypePgDown:
+		offset += page
+	case KeyTypeHome:
+		offset = 0
+	case KeyTypeEnd:
+		if !known {
+			return false
+		}
+		offset = math.MaxInt
+	default:
+		return false
+	}
+	return d.ScrollTo(view, offset)
+}
+
+// ScrollIntoView scrolls the nearest scroll view around node so that its
+// child holding node is entirely in view. It reports whether OnScroll was called.
+func (d *DOM) ScrollIntoView(node *Node) bool {
+	item, view := node, node.Parent
+	for view != nil && view.Type != ElementTypeScrollView {
+		item, view = view, view.Parent
+	}
+	if view == nil {
+		return false
+	}
+	props := ExtractProps[ScrollViewProps](view.Props)
+	extent, _ := d.scrollExtent(view, props)
+
+	var top, height int
+	if props.ItemHeight > 0 {
+		index := -1
+		for i, child := range view.Children {
+			if child == item {
+				index = i
+				break
+			}
+		}
+		top, height = index*props.ItemHeight, props.ItemHeight
+	} else {
+		m, ok := d.HitTester.(ScrollMeasurer)
+		if !ok {
+			return false
+		}
+		bounds, ok := m.BoundsOf(item)
+		if !ok {
+			return false
+		}
+		top, height = bounds.Y-extent.Viewport.Y+extent.Offset, bounds.Height
+	}
+
+	switch {
+	case top < extent.Offset:
+		return d.ScrollTo(view, top)
+	case top+height > extent.Offset+extent.Viewport.Height:
+		return d.ScrollTo(view, top+height-extent.Viewport.Height)
+	}
+	return false
+}
+
+// closestScrollView returns node or its nearest ancestor that is a scroll view
+func (c *Node) closestScrollView() *Node {
+	for n := c; n != nil; n = n.Parent {
+		if n.Type == ElementTypeScrollView {
+			return n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package dom
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// scrollList builds a scroll view of n one line items showing 3 of them,
+// recording every offset OnScroll is called with and every item focused
+func scrollList(n, offset, focused int, scrolls, focuses *[]int) *Node {
+	items := make([]*Node, n)
+	for i := range items {
+		items[i] = TextWithProps(fmt.Sprintf("item %d", i), TextNodeProps{
+			Focusable: true,
+			Focused:   i == focused,
+			OnFocus:   func() { *focuses = append(*focuses, i) },
+		})
+	}
+	return ScrollView(ScrollViewProps{
+		Height:       3,
+		ItemHeight:   1,
+		ScrollOffset: offset,
+		OnScroll:     func(offset int) { *scrolls = append(*scrolls, offset) },
+		Focusable:    true,
+		Focused:      focused < 0,
+	}, items...)
+}
+
+func TestScrollViewKeys(t *testing.T) {
+	tests := []struct {
+		key      KeyType
+		offset   int
+		expected []int
+	}{
+		{KeyTypeDown, 0, []int{1}},
+		{KeyTypeUp, 0, nil},
+		{KeyTypePgDown, 2, []int{5}},
+		{KeyTypePgDown, 6, []int{7}},
+		{KeyTypePgUp, 2, []int{0}},
+		{KeyTypeHome, 4, []int{0}},
+		{KeyTypeEnd, 0, []int{7}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s from %d", tt.key, tt.offset), func(t *testing.T) {
+			var scrolls []int
+			d := NewDOM(scrollList(10, tt.offset, -1, &scrolls, new([]int)), &Window{})
+			d.DispatchKeyDownEvent(&KeydownEvent{KeyType: tt.key})
+			if !reflect.DeepEqual(scrolls, tt.expected) {
+				t.Errorf("expected scrolls %v, got %v", tt.expected, scrolls)
+			}
+		})
+	}
+}
+
+func TestScrollViewWheel(t *testing.T) {
+	var scrolls []int
+	view := scrollList(10, 6, -1, &scrolls, new([]int))
+	d := NewDOM(view, &Window{})
+	d.HitTester = &fixedHitTester{node: view.Children[7]}
+
+	d.DispatchMouseEvent(&MouseEvent{Action: MouseActionWheel, DeltaY: 3})
+	if expected := []int{7}; !reflect.DeepEqual(scrolls, expected) {
+		t.Errorf("expected scroll clamped to %v, got %v", expected, scrolls)
+	}
+}
+
+func TestFocusNavigationScrollsIntoView(t *testing.T) {
+	var scrolls []int
+	var focused []int
+	view := scrollList(10, 0, 2, &scrolls, &focused)
+	d := NewDOM(view, &Window{})
+
+	d.DispatchKeyDownEvent(&KeydownEvent{KeyType: KeyTypeDown})
+	if !reflect.DeepEqual(focused, []int{3}) {
+		t.Fatalf("expected item 3 focused, got %v", focused)
+	}
+	if expected := []int{1}; !reflect.DeepEqual(scrolls, expected) {
+		t.Errorf("expected scrolls %v, got %v", expected, scrolls)
+	}
+}
+
+// scrollMeasurer measures every scroll view with the same extent
+type scrollMeasurer struct {
+	boundsMap
+	extent ScrollExtent
+}
+
+func (m scrollMeasurer) ScrollExtentOf(node *Node) (ScrollExtent, bool) {
+	return m.extent, true
+}
+
+func TestScrollToClampsToMeasuredContent(t *testing.T) {
+	var scrolls []int
+	list := func() *Node {
+		// without ItemHeight, only the layout knows the content height
+		return ScrollView(ScrollViewProps{
+			OnScroll: func(offset int) { scrolls = append(scrolls, offset) },
+		}, Text("a"), Text("b"), Text("c"), Text("d"), Text("e"))
+	}
+
+	prev := NewDOM(list(), &Window{})
+	prev.HitTester = scrollMeasurer{extent: ScrollExtent{Viewport: Rect{Height: 3}, ContentHeight: 5}}
+	prev.ScrollTo(prev.Root, 100)
+
+	// the next render, before its layout is known
+	d := NewDOM(list(), &Window{})
+	d.Inherit(prev)
+	d.ScrollTo(d.Root, 100)
+
+	if expected := []int{2, 2}; !reflect.DeepEqual(scrolls, expected) {
+		t.Errorf("expected scrolls %v, got %v", expected, scrolls)
+	}
+}
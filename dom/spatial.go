@@ -0,0 +1,114 @@
+package dom
+
+// BoundsMeasurer reports where nodes were rendered on screen.
+// The layout of the last render implements it alongside HitTester.
+type BoundsMeasurer interface {
+	BoundsOf(node *Node) (Rect, bool)
+}
+
+// spatialContainer returns the closest ancestor of c, c included, with
+// the spatialNavigation prop set, or nil
+func (c *Node) spatialContainer() *Node {
+	for n := c; n != nil; n = n.Parent {
+		if n.Props == nil {
+			continue
+		}
+		if v, ok := n.Props.Get("spatialNavigation"); ok {
+			if spatial, _ := v.(bool); spatial {
+				return n
+			}
+		}
+	}
+	return nil
+}
+
+// moveFocusSpatially moves the focus to the nearest node on screen in the
+// direction of an arrow key, among the focusable nodes of the spatial
+// container around the focused node. It reports whether it found one.
+func (d *DOM) moveFocusSpatially(key KeyType) bool {
+	switch key {
+	case KeyTypeUp, KeyTypeDown, KeyTypeLeft, KeyTypeRight:
+	default:
+		return false
+	}
+	from := d.FocusedNode
+	if from == nil {
+		return false
+	}
+	container := from.spatialContainer()
+	if container == nil {
+		return false
+	}
+	m, ok := d.HitTester.(BoundsMeasurer)
+	if !ok {
+		return false
+	}
+	fromBounds, ok := m.BoundsOf(from)
+	if !ok {
+		return false
+	}
+
+	var target *Node
+	best := 0
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		if node == nil {
+			return
+		}
+		if index, _ := node.GetTabIndex(); node != from && index >= 0 && node.IsFocusable() && d.canFocus(node) {
+			if bounds, ok := m.BoundsOf(node); ok {
+				if distance, ok := spatialDistance(fromBounds, bounds, key); ok && (target == nil || distance < best) {
+					target, best = node, distance
+				}
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(container)
+	if target == nil {
+		return false
+	}
+	d.moveFocusTo(target)
+	d.ScrollIntoView(target)
+	return true
+}
+
+// spatialDistance scores how far to rect is from from in the direction of
+// key, lower is nearer. ok is false if to is not in that direction.
+// Being off the line of from counts double, so that the node next to
+// from wins over a closer one diagonally.
+func spatialDistance(from, to Rect, key KeyType) (distance int, ok bool) {
+	var along, across int
+	switch key {
+	case KeyTypeRight:
+		along = to.X - (from.X + from.Width)
+		across = rangeGap(from.Y, from.Height, to.Y, to.Height)
+	case KeyTypeLeft:
+		along = from.X - (to.X + to.Width)
+		across = rangeGap(from.Y, from.Height, to.Y, to.Height)
+	case KeyTypeDown:
+		along = to.Y - (from.Y + from.Height)
+		across = rangeGap(from.X, from.Width, to.X, to.Width)
+	case KeyTypeUp:
+		along = from.Y - (to.Y + to.Height)
+		across = rangeGap(from.X, from.Width, to.X, to.Width)
+	}
+	if along < 0 {
+		return 0, false
+	}
+	return along + 2*across, true
+}
+
+// rangeGap returns how far apart the ranges [a, a+aLen) and [b, b+bLen)
+// are: 0 if they overlap, 1 if they touch
+func rangeGap(a, aLen, b, bLen int) int {
+	switch {
+	case b >= a+aLen:
+		return b - (a + aLen) + 1
+	case a >= b+bLen:
+		return a - (b + bLen) + 1
+	}
+	return 0
+}
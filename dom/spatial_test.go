@@ -0,0 +1,71 @@
+package dom
+
+import "testing"
+
+// boundsMap places nodes where a test says they were rendered
+type boundsMap map[*Node]Rect
+
+func (b boundsMap) NodeAt(x, y int) *Node {
+	return nil
+}
+
+func (b boundsMap) BoundsOf(node *Node) (Rect, bool) {
+	r, ok := b[node]
+	return r, ok
+}
+
+func TestSpatialNavigation(t *testing.T) {
+	var focused string
+	cell := func(name string) *Node {
+		return Div(DivProps{
+			Focusable: true,
+			Focused:   focused == name,
+			OnFocus:   func() { focused = name },
+		}, Text(name))
+	}
+
+	// a b
+	// c d e   (e is taller and spans both rows on the right)
+	// f       (outside the grid)
+	press := func(from string, key KeyType) string {
+		focused = from
+		a, b, c, d, e, f := cell("a"), cell("b"), cell("c"), cell("d"), cell("e"), cell("f")
+		grid := Div(DivProps{SpatialNavigation: true},
+			HDiv(DivProps{}, a, b),
+			HDiv(DivProps{}, c, d),
+			e,
+		)
+		dom := NewDOM(Div(DivProps{}, grid, f), &Window{})
+		dom.HitTester = boundsMap{
+			a: {X: 0, Y: 0, Width: 4, Height: 1},
+			b: {X: 5, Y: 0, Width: 4, Height: 1},
+			c: {X: 0, Y: 1, Width: 4, Height: 1},
+			d: {X: 5, Y: 1, Width: 4, Height: 1},
+			e: {X: 10, Y: 0, Width: 4, Height: 2},
+			f: {X: 0, Y: 3, Width: 4, Height: 1},
+		}
+		dom.DispatchKeyDownEvent(&KeydownEvent{KeyType: key})
+		return focused
+	}
+
+	tests := []struct {
+		from     string
+		key      KeyType
+		expected string
+	}{
+		{"a", KeyTypeRight, "b"},
+		{"a", KeyTypeDown, "c"},
+		{"d", KeyTypeUp, "b"},
+		{"d", KeyTypeLeft, "c"},
+		{"d", KeyTypeRight, "e"},
+		{"e", KeyTypeLeft, "b"},
+		{"a", KeyTypeLeft, "a"},
+		// nothing below in the grid, down leaves it in document order
+		{"e", KeyTypeDown, "f"},
+	}
+	for _, tt := range tests {
+		if got := press(tt.from, tt.key); got != tt.expected {
+			t.Errorf("%s from %s: expected %s, got %s", tt.key, tt.from, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package dom
+
+import "unicode"
+
+// TextEdit is the part of a text field a key press edits: its value,
+// the cursor and the selection, as rune offsets into the value
+type TextEdit struct {
+	Value  string
+	Cursor int
+	Anchor *int // Other end of the selection, the cursor being one end; nil when nothing is selected
+}
+
+// Selection returns the selected range [start, end), ok is false
+// when nothing is selected
+func (t TextEdit) Selection() (start, end int, ok bool) {
+	if t.Anchor == nil || *t.Anchor == t.Cursor {
+		return t.Cursor, t.Cursor, false
+	}
+	return min(*t.Anchor, t.Cursor), max(*t.Anchor, t.Cursor), true
+}
+
+// clamped returns t with the cursor and the anchor inside the value of
+// n runes, which apps may have shortened without moving them
+func (t TextEdit) clamped(n int) TextEdit {
+	t.Cursor = min(max(t.Cursor, 0), n)
+	if t.Anchor != nil {
+		anchor := min(max(*t.Anchor, 0), n)
+		t.Anchor = &anchor
+	}
+	return t
+}
+
+// EditText applies a key press to a multi-line text field:
+//   - arrows move by rune and by line, Home/End and Ctrl-A/E to the ends of the line
+//   - Alt-B/F move by word, Alt-A selects all
+//   - shift with an arrow, Home or End extends the selection
+//   - Backspace, Delete, Ctrl-W and Ctrl-K delete, the selection first if any
+//   - Enter and typed or pasted runes insert, replacing the selection
+//
+// maxLength limits the value to that many runes when positive.
+// ok is false if the key is not an editing key.
+func EditText(t TextEdit, e *KeydownEvent, maxLength int) (result TextEdit, ok bool) {
+	runes := []rune(t.Value)
+	t = t.clamped(len(runes))
+	start, end, selected := t.Selection()
+
+	switch e.KeyType {
+	case KeyTypeLeft, KeyTypeRight:
+		if selected {
+			// collapse the selection to the side moved to
+			if e.KeyType == KeyTypeLeft {
+				return TextEdit{Value: t.Value, Cursor: start}, true
+			}
+			return TextEdit{Value: t.Value, Cursor: end}, true
+		}
+		return TextEdit{Value: t.Value, Cursor: cursorTarget(runes, t.Cursor, e.KeyType)}, true
+	case KeyTypeUp, KeyTypeDown, KeyTypeHome, KeyTypeEnd, KeyTypeCtrlA, KeyTypeCtrlE:
+		return TextEdit{Value: t.Value, Cursor: cursorTarget(runes, t.Cursor, e.KeyType)}, true
+	case KeyTypeShiftLeft, KeyTypeShiftRight, KeyTypeShiftUp, KeyTypeShiftDown, KeyTypeShiftHome, KeyTypeShiftEnd:
+		anchor := t.Cursor
+		if t.Anchor != nil {
+			anchor = *t.Anchor
+		}
+		cursor := cursorTarget(runes, t.Cursor, unshifted[e.KeyType])
+		if cursor == anchor {
+			return TextEdit{Value: t.Value, Cursor: cursor}, true
+		}
+		return TextEdit{Value: t.Value, Cursor: cursor, Anchor: &anchor}, true
+	case KeyTypeBackspace:
+		if selected {
+			return replaceText(runes, start, end, nil, maxLength), true
+		}
+		if t.Cursor == 0 {
+			return TextEdit{Value: t.Value}, true
+		}
+		return replaceText(runes, t.Cursor-1, t.Cursor, nil, maxLength), true
+	case KeyTypeDelete:
+		if selected {
+			return replaceText(runes, start, end, nil, maxLength), true
+		}
+		return replaceText(runes, t.Cursor, min(t.Cursor+1, len(runes)), nil, maxLength), true
+	case KeyTypeCtrlW:
+		if selected {
+			return replaceText(runes, start, end, nil, maxLength), true
+		}
+		return replaceText(runes, wordStart(runes, t.Cursor), t.Cursor, nil, maxLength), true
+	case KeyTypeCtrlK:
+		if selected {
+			return replaceText(runes, start, end, nil, maxLength), true
+		}
+		kill := lineEnd(runes, t.Cursor)
+		if kill == t.Cursor && kill < len(runes) {
+			// at the end of the line, join the next one
+			kill++
+		}
+		return replaceText(runes, t.Cursor, kill, nil, maxLength), true
+	case KeyTypeEnter:
+		return replaceText(runes, start, end, []rune{'\n'}, maxLength), true
+	}
+
+	if len(e.Runes) == 0 {
+		return t, false
+	}
+	if e.Alt {
+		switch e.Runes[0] {
+		case 'a':
+			if len(runes) == 0 {
+				return TextEdit{}, true
+			}
+			anchor := 0
+			return TextEdit{Value: t.Value, Cursor: len(runes), Anchor: &anchor}, true
+		case 'b':
+			return TextEdit{Value: t.Value, Cursor: wordStart(runes, t.Cursor)}, true
+		case 'f':
+			return TextEdit{Value: t.Value, Cursor: wordEnd(runes, t.Cursor)}, true
+		}
+		return t, false
+	}
+	return replaceText(runes, start, end, e.Runes, maxLength), true
+}
+
+// unshifted maps the selecting keys to the movement they extend the selection by
+var unshifted = map[KeyType]KeyType{
+	KeyTypeShiftLeft:  KeyTypeLeft,
+	KeyTypeShiftRight: KeyTypeRight,
+	KeyTypeShiftUp:    KeyTypeUp,
+	KeyTypeShiftDown:  KeyTypeDown,
+	KeyTypeShiftHome:  KeyTypeHome,
+	KeyTypeShiftEnd:   KeyTypeEnd,
+}
+
+// cursorTarget returns where a movement key takes the cursor at pos
+func cursorTarget(runes []rune, pos int, key KeyType) int {
+	switch key {
+	case KeyTypeLeft:
+		return max(pos-1, 0)
+	case KeyTypeRight:
+		return min(pos+1, len(runes))
+	case KeyTypeHome, KeyTypeCtrlA:
+		return lineStart(runes, pos)
+	case KeyTypeEnd, KeyTypeCtrlE:
+		return lineEnd(runes, pos)
+	case KeyTypeUp:
+		start := lineStart(runes, pos)
+		if start == 0 {
+			return 0
+		}
+		prev := lineStart(runes, start-1)
+		return min(prev+pos-start, start-1)
+	case KeyTypeDown:
+		end := lineEnd(runes, pos)
+		if end == len(runes) {
+			return end
+		}
+		return min(end+1+pos-lineStart(runes, pos), lineEnd(runes, end+1))
+	}
+	return pos
+}
+
+// replaceText replaces runes[start:end] with insert, cut to what
+// maxLength leaves room for, and puts the cursor after it
+func replaceText(runes []rune, start, end int, insert []rune, maxLength int) TextEdit {
+	if maxLength > 0 {
+		room := max(maxLength-(len(runes)-(end-start)), 0)
+		insert = insert[:min(len(insert), room)]
+	}
+	value := make([]rune, 0, len(runes)-(end-start)+len(insert))
+	value = append(value, runes[:start]...)
+	value = append(value, insert...)
+	value = append(value, runes[end:]...)
+	return TextEdit{Value: string(value), Cursor: start + len(insert)}
+}
+
+func lineStart(runes []rune, pos int) int {
+	for pos > 0 && runes[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+func lineEnd(runes []rune, pos int) int {
+	for pos < len(runes) && runes[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wordStart returns the start of the word before pos
+func wordStart(runes []rune, pos int) int {
+	for pos > 0 && !isWordRune(runes[pos-1]) {
+		pos--
+	}
+	for pos > 0 && isWordRune(runes[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+// wordEnd returns the end of the word after pos
+func wordEnd(runes []rune, pos int) int {
+	for pos < len(runes) && !isWordRune(runes[pos]) {
+		pos++
+	}
+	for pos < len(runes) && isWordRune(runes[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// maxTextHistory is how many edits a TextHistory can undo
+const maxTextHistory = 100
+
+// TextHistory records the edits of a text area for undo (Ctrl-Z) and
+// redo (Ctrl-Y). Like the value, it is owned by the app: keep one per
+// text area across renders and pass it in the History prop.
+// The zero value is an empty history.
+type TextHistory struct {
+	undo []TextEdit
+	redo []TextEdit
+
+	// last is the result of the last edit recorded if it was typing,
+	// which the next typing extends instead of adding an edit
+	last *TextEdit
+}
+
+// record adds the edit from before to after
+func (h *TextHistory) record(before, after TextEdit, typing bool) {
+	h.redo = nil
+	if typing && h.last != nil && h.last.Value == before.Value && h.last.Cursor == before.Cursor {
+		h.last = &after
+		return
+	}
+	h.undo = append(h.undo, before)
+	if len(h.undo) > maxTextHistory {
+		h.undo = h.undo[len(h.undo)-maxTextHistory:]
+	}
+	h.last = nil
+	if typing {
+		h.last = &after
+	}
+}
+
+// Undo returns the state before the last edit, current being the state
+// now. ok is false if there is nothing to undo.
+func (h *TextHistory) Undo(current TextEdit) (TextEdit, bool) {
+	if len(h.undo) == 0 {
+		return current, false
+	}
+	prev := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, current)
+	h.last = nil
+	return prev, true
+}
+
+// Redo returns the state the last Undo went back from.
+// ok is false if there is nothing to redo.
+func (h *TextHistory) Redo(current TextEdit) (TextEdit, bool) {
+	if len(h.redo) == 0 {
+		return current, false
+	}
+	next := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, current)
+	h.last = nil
+	return next, true
+}
+
+// handleTextAreaKey applies an editing key to a text area and calls its
+// OnChange, OnCursorMove and OnSelect. It reports whether key was one.
+func (d *DOM) handleTextAreaKey(node *Node, keyEvent *KeydownEvent) bool {
+	props := ExtractProps[TextAreaProps](node.Props)
+	before := TextEdit{Value: props.Value, Cursor: props.CursorPosition, Anchor: props.SelectionAnchor}
+
+	after, ok := d.clipboardEdit(before, keyEvent, props.MaxLength)
+	switch {
+	case ok:
+		if props.History != nil && after.Value != before.Value {
+			props.History.record(before, after, false)
+		}
+	case keyEvent.KeyType == KeyTypeCtrlZ || keyEvent.KeyType == KeyTypeCtrlY:
+		if props.History == nil {
+			return false
+		}
+		if keyEvent.KeyType == KeyTypeCtrlZ {
+			after, ok = props.History.Undo(before)
+		} else {
+			after, ok = props.History.Redo(before)
+		}
+	default:
+		after, ok = EditText(before, keyEvent, props.MaxLength)
+		if ok && props.History != nil && after.Value != before.Value {
+			typing := len(keyEvent.Runes) > 0 && !keyEvent.Paste && keyEvent.KeyType != KeyTypeEnter
+			props.History.record(before, after, typing)
+		}
+	}
+	if !ok {
+		return false
+	}
+	notifyTextEdit(before, after, props.OnChange, props.OnCursorMove, props.OnSelect)
+	return true
+}
+
+// notifyTextEdit calls the handlers of a text field for what changed from before to after
+func notifyTextEdit(before, after TextEdit, onChange func(string), onCursorMove func(int), onSelect func(*int)) {
+	if after.Value != before.Value && onChange != nil {
+		onChange(after.Value)
+	}
+	if after.Cursor != before.Cursor && onCursorMove != nil {
+		onCursorMove(after.Cursor)
+	}
+	if !sameAnchor(after.Anchor, before.Anchor) && onSelect != nil {
+		onSelect(after.Anchor)
+	}
+}
+
+func sameAnchor(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
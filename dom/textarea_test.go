@@ -0,0 +1,126 @@
+package dom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditText(t *testing.T) {
+	key := func(k KeyType) *KeydownEvent { return &KeydownEvent{KeyType: k} }
+	runes := func(s string) *KeydownEvent { return &KeydownEvent{Runes: []rune(s)} }
+	alt := func(r rune) *KeydownEvent { return &KeydownEvent{Runes: []rune{r}, Alt: true} }
+	anchor := func(n int) *int { return &n }
+
+	tests := []struct {
+		name      string
+		state     TextEdit
+		event     *KeydownEvent
+		maxLength int
+		expected  TextEdit
+	}{
+		{"Insert", TextEdit{Value: "ac", Cursor: 1}, runes("b"), 0, TextEdit{Value: "abc", Cursor: 2}},
+		{"Newline", TextEdit{Value: "ab", Cursor: 1}, key(KeyTypeEnter), 0, TextEdit{Value: "a\nb", Cursor: 2}},
+		{"MaxLength", TextEdit{Value: "ab", Cursor: 2}, runes("cde"), 3, TextEdit{Value: "abc", Cursor: 3}},
+		{"Backspace joins lines", TextEdit{Value: "a\nb", Cursor: 2}, key(KeyTypeBackspace), 0, TextEdit{Value: "ab", Cursor: 1}},
+		{"Up keeps column", TextEdit{Value: "abc\nde", Cursor: 5}, key(KeyTypeUp), 0, TextEdit{Value: "abc\nde", Cursor: 1}},
+		{"Up to shorter line", TextEdit{Value: "a\nbcd", Cursor: 5}, key(KeyTypeUp), 0, TextEdit{Value: "a\nbcd", Cursor: 1}},
+		{"Up on first line", TextEdit{Value: "abc", Cursor: 2}, key(KeyTypeUp), 0, TextEdit{Value: "abc", Cursor: 0}},
+		{"Down keeps column", TextEdit{Value: "abc\ndef", Cursor: 2}, key(KeyTypeDown), 0, TextEdit{Value: "abc\ndef", Cursor: 6}},
+		{"Down to shorter line", TextEdit{Value: "abc\nd\ne", Cursor: 3}, key(KeyTypeDown), 0, TextEdit{Value: "abc\nd\ne", Cursor: 5}},
+		{"Home", TextEdit{Value: "ab\ncd", Cursor: 4}, key(KeyTypeHome), 0, TextEdit{Value: "ab\ncd", Cursor: 3}},
+		{"End", TextEdit{Value: "ab\ncd", Cursor: 0}, key(KeyTypeCtrlE), 0, TextEdit{Value: "ab\ncd", Cursor: 2}},
+		{"Word back", TextEdit{Value: "foo bar.baz", Cursor: 11}, alt('b'), 0, TextEdit{Value: "foo bar.baz", Cursor: 8}},
+		{"Word forward", TextEdit{Value: "foo bar", Cursor: 3}, alt('f'), 0, TextEdit{Value: "foo bar", Cursor: 7}},
+		{"Delete word", TextEdit{Value: "foo bar", Cursor: 7}, key(KeyTypeCtrlW), 0, TextEdit{Value: "foo ", Cursor: 4}},
+		{"Kill line", TextEdit{Value: "ab\ncd", Cursor: 1}, key(KeyTypeCtrlK), 0, TextEdit{Value: "a\ncd", Cursor: 1}},
+		{"Kill newline", TextEdit{Value: "ab\ncd", Cursor: 2}, key(KeyTypeCtrlK), 0, TextEdit{Value: "abcd", Cursor: 2}},
+		{"Select", TextEdit{Value: "abc", Cursor: 1}, key(KeyTypeShiftRight), 0, TextEdit{Value: "abc", Cursor: 2, Anchor: anchor(1)}},
+		{"Extend selection", TextEdit{Value: "abc\ndef", Cursor: 2, Anchor: anchor(1)}, key(KeyTypeShiftDown), 0, TextEdit{Value: "abc\ndef", Cursor: 6, Anchor: anchor(1)}},
+		{"Unselect", TextEdit{Value: "abc", Cursor: 2, Anchor: anchor(1)}, key(KeyTypeShiftLeft), 0, TextEdit{Value: "abc", Cursor: 1}},
+		{"Collapse selection", TextEdit{Value: "abc", Cursor: 3, Anchor: anchor(1)}, key(KeyTypeLeft), 0, TextEdit{Value: "abc", Cursor: 1}},
+		{"Replace selection", TextEdit{Value: "abcd", Cursor: 1, Anchor: anchor(3)}, runes("x"), 0, TextEdit{Value: "axd", Cursor: 2}},
+		{"Delete selection", TextEdit{Value: "ab\ncd", Cursor: 4, Anchor: anchor(1)}, key(KeyTypeBackspace), 0, TextEdit{Value: "ad", Cursor: 1}},
+		{"Paste", TextEdit{Value: "", Cursor: 0}, &KeydownEvent{Runes: []rune("a\nb"), Paste: true}, 0, TextEdit{Value: "a\nb", Cursor: 3}},
+		// apps may shorten the value without moving the anchor
+		{"Stale anchor", TextEdit{Value: "abc", Cursor: 1, Anchor: anchor(10)}, key(KeyTypeBackspace), 0, TextEdit{Value: "a", Cursor: 1}},
+		{"Negative anchor", TextEdit{Value: "abc", Cursor: 2, Anchor: anchor(-5)}, key(KeyTypeDelete), 0, TextEdit{Value: "c", Cursor: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := EditText(tt.state, tt.event, tt.maxLength)
+			if !ok {
+				t.Fatalf("expected key to be handled")
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, ok := EditText(TextEdit{}, key(KeyTypeEsc), 0); ok {
+		t.Errorf("expected esc not to be an editing key")
+	}
+}
+
+// textAreaApp keeps the state of a text area like an app would
+type textAreaApp struct {
+	value   string
+	cursor  int
+	anchor  *int
+	history TextHistory
+}
+
+func (a *textAreaApp) press(events ...*KeydownEvent) {
+	for _, e := range events {
+		node := TextArea(TextAreaProps{
+			Value:           a.value,
+			CursorPosition:  a.cursor,
+			SelectionAnchor: a.anchor,
+			History:         &a.history,
+			Focused:         true,
+			OnChange:        func(s string) { a.value = s },
+			OnCursorMove:    func(p int) { a.cursor = p },
+			OnSelect:        func(p *int) { a.anchor = p },
+		})
+		NewDOM(node, &Window{}).DispatchKeyDownEvent(e)
+	}
+}
+
+func TestTextAreaUndoRedo(t *testing.T) {
+	app := &textAreaApp{}
+	typeText := func(s string) {
+		for _, r := range s {
+			app.press(&KeydownEvent{Runes: []rune{r}})
+		}
+	}
+	typeText("hello")
+	app.press(&KeydownEvent{KeyType: KeyTypeEnter})
+	typeText("world")
+
+	undo := &KeydownEvent{KeyType: KeyTypeCtrlZ}
+	app.press(undo)
+	if app.value != "hello\n" || app.cursor != 6 {
+		t.Fatalf("expected typing undone as one edit, got %q at %d", app.value, app.cursor)
+	}
+	app.press(undo, undo)
+	if app.value != "" {
+		t.Fatalf("expected everything undone, got %q", app.value)
+	}
+	app.press(&KeydownEvent{KeyType: KeyTypeCtrlY}, &KeydownEvent{KeyType: KeyTypeCtrlY})
+	if app.value != "hello\n" {
+		t.Fatalf("expected redo, got %q", app.value)
+	}
+	typeText("!")
+	app.press(&KeydownEvent{KeyType: KeyTypeCtrlY})
+	if app.value != "hello\n!" {
+		t.Errorf("expected an edit to clear the redo history, got %q", app.value)
+	}
+}
+
+func TestTextAreaKeepsArrowKeys(t *testing.T) {
+	app := &textAreaApp{value: "ab\ncd", cursor: 4}
+	app.press(&KeydownEvent{KeyType: KeyTypeUp}, &KeydownEvent{KeyType: KeyTypeShiftEnd})
+	if app.cursor != 2 || app.anchor == nil || *app.anchor != 1 {
+		t.Errorf("expected up and shift+end to move and select in the text area, got cursor %d anchor %v", app.cursor, app.anchor)
+	}
+}
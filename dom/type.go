@@ -12,10 +12,16 @@ const (
 	ElementTypeH2          = "h2"
 	ElementTypeP           = "p"
 	ElementTypeInput       = "input"
+	ElementTypeTextArea    = "textarea" // Multi-line text field, see TextAreaProps
 	ElementTypeButton      = "button"
 	ElementTypeUl          = "ul"
 	ElementTypeLi          = "li"
 	ElementTypeFragment    = "fragment"
 	ElementTypeSpacer      = "spacer"
 	ElementTypeFixedSpacer = "fixed_spacer"
+	ElementTypeScrollView  = "scrollview" // Scrollable viewport, see ScrollViewProps
+	ElementTypeComponent   = "component"  // Function component, see CreateComponent
+	ElementTypeFocusScope  = "focusscope" // Confines focus traversal, see FocusScopeProps
+	ElementTypeKeyMap      = "keymap"     // Binds keys for its children, see KeyMapProps
+	ElementTypeKeyHelp     = "keyhelp"    // Lists the bindings active, see KeyHelpProps
 )
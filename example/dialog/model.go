@@ -4,6 +4,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/xhd2015/go-dom-tui/charm/renderer"
 	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
 )
 
 type Model struct {
@@ -89,7 +90,12 @@ func (m *Model) renderView(stripColor bool) string {
 	// If dialog is shown, use ZDiv to overlay it
 	var root *dom.Node
 	if m.showDialog {
-		dialog := dom.Div(dom.DivProps{},
+		// the background makes the whole dialog opaque: blank cells
+		// without one let the list below show through
+		dialog := dom.Div(dom.DivProps{
+			Style:    styles.Style{BackgroundColor: "236"},
+			Position: dom.Position{CenterX: true, CenterY: true},
+		},
 			dom.Text("+---------------------------+"),
 			dom.Text("|   Dialog Overlay Demo    |"),
 			dom.Text("|                           |"),
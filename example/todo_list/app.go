@@ -2,17 +2,12 @@ package main
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/xhd2015/go-dom-tui/dom"
 	"github.com/xhd2015/go-dom-tui/log"
 	"github.com/xhd2015/go-dom-tui/styles"
 )
 
-const (
-	CtrlCTimeoutMs = 1000
-)
-
 type AppState struct {
 	Todos  []TodoItem
 	Status string // Current status message
@@ -24,9 +19,6 @@ type AppState struct {
 	SelectedTodoIndex int
 
 	Quitting bool
-
-	LastCtrlCTime time.Time
-	Refresh       func()
 }
 
 func (m *AppState) OnChangeSelectedTodoIndex(index int) {
@@ -93,24 +85,15 @@ func App(props *AppState, window *dom.Window) *dom.Node {
 		return dom.Div(dom.DivProps{}, dom.Text("Thanks for using Quick Todo!"))
 	}
 
-	return dom.Div(dom.DivProps{
+	quit := func() {
+		log.Logf("App: quitting by double ctrl-c ")
+		props.Quitting = true
+	}
+
+	return dom.KeyMap(dom.KeyMapProps{Bindings: []dom.Binding{
+		{Keys: "ctrl+c ctrl+c", Description: "quit", Handler: quit},
+	}}, dom.Div(dom.DivProps{
 		Style: styles.Style{}, // No border by default
-		OnKeyDown: func(event *dom.DOMEvent) {
-			if event.KeydownEvent.KeyType == dom.KeyTypeCtrlC {
-				if time.Since(props.LastCtrlCTime) < CtrlCTimeoutMs*time.Millisecond {
-					log.Logf("App: quitting by double ctrl-c ")
-					props.Quitting = true
-				} else {
-					go func() {
-						time.Sleep(CtrlCTimeoutMs * time.Millisecond)
-						if props.Refresh != nil {
-							props.Refresh()
-						}
-					}()
-				}
-				props.LastCtrlCTime = time.Now()
-			}
-		},
 		OnWindowResize: func(event *dom.DOMEvent) {
 			width, height := event.WindowEvent.Width, event.WindowEvent.Height
 			log.Logf("App: window resized to %dx%d", width, height)
@@ -158,14 +141,8 @@ func App(props *AppState, window *dom.Window) *dom.Node {
 			}),
 		),
 
-		func() *dom.Node {
-			if time.Since(props.LastCtrlCTime) < CtrlCTimeoutMs*time.Millisecond {
-
-				// red color - need to handle this differently since it's not a div style
-				return dom.P(dom.DivProps{}, dom.Text("Ctrl-C again to quit."))
-			}
-			return nil
-		}(),
+		// "ctrl+c  quit" after the first ctrl+c
+		dom.KeyHelp(dom.KeyHelpProps{PendingOnly: true}),
 
 		// Todo list section (only show if there are todos)
 		func() *dom.Node {
@@ -179,5 +156,5 @@ func App(props *AppState, window *dom.Window) *dom.Node {
 			return dom.Div(dom.DivProps{},
 				dom.P(dom.DivProps{}, dom.Text("📝 No tasks yet. Start typing above!")))
 		}(),
-	)
+	))
 }
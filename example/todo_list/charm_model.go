@@ -3,7 +3,6 @@ package main
 import (
 	"os"
 
-	"github.com/charmbracelet/bubbles/cursor"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/xhd2015/go-dom-tui/charm"
 	"github.com/xhd2015/go-dom-tui/charm/renderer"
@@ -49,9 +48,6 @@ func NewModel(debugFilePath string) *Model {
 	appState := AppState{
 		InputFocused:      true, // Start with input focused
 		SelectedTodoIndex: -1,
-		Refresh: func() {
-			m.program.Send(cursor.Blink())
-		},
 	}
 	m.app = charm.NewCharmApp(&appState, App)
 
@@ -43,6 +43,7 @@ func handle(args []string) error {
 	model := NewModel(debugLog)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	model.program = p
+	model.app.SetProgram(p)
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running program: %w", err)
 	}
@@ -3,6 +3,8 @@ package react
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 
 	"github.com/xhd2015/go-dom-tui/dom"
 )
@@ -86,8 +88,86 @@ func diffNodes(oldNode, newNode *dom.Node, path []int, result *DiffResult) {
 	}
 }
 
-// diffChildren diffs child nodes with key-based reconciliation
+// diffChildren diffs child nodes with key-based reconciliation.
+// Children are matched by key, or by index when they have none. Old children
+// without a match are deleted, at their old index; new children without a
+// match are created, and matched children that have to move are reordered,
+// at their new index. Deletions come first, from the last old child to the
+// first, so that each one still finds its child at its old index when the
+// patches are applied in order. Reorders name the old index they move from,
+// From, as it was before the deletions: appliers find the child by OldNode.
 func diffChildren(oldChildren, newChildren []*dom.Node, path []int, result *DiffResult) {
+	if !hasKeys(oldChildren) && !hasKeys(newChildren) {
+		diffChildrenByIndex(oldChildren, newChildren, path, result)
+		return
+	}
+
+	oldIndex := make(map[string]int, len(oldChildren))
+	for i, child := range oldChildren {
+		if child == nil {
+			continue
+		}
+		key := childKey(i, child)
+		if _, dup := oldIndex[key]; !dup {
+			oldIndex[key] = i
+		}
+	}
+
+	// sources[i] is the old index of new child i, or -1 if it is new
+	sources := make([]int, len(newChildren))
+	matched := make([]bool, len(oldChildren))
+	for i, child := range newChildren {
+		sources[i] = -1
+		if child == nil {
+			continue
+		}
+		if j, ok := oldIndex[childKey(i, child)]; ok && !matched[j] {
+			sources[i] = j
+			matched[j] = true
+		}
+	}
+
+	for j := len(oldChildren) - 1; j >= 0; j-- {
+		if child := oldChildren[j]; child != nil && !matched[j] {
+			result.Patches = append(result.Patches, Patch{
+				Type:    PatchDelete,
+				Path:    childPath(path, j),
+				OldNode: child,
+			})
+		}
+	}
+
+	// children in the longest run that kept their relative order stay,
+	// every other matched child moves
+	stay := longestIncreasing(sources)
+	for i, child := range newChildren {
+		if child == nil {
+			continue
+		}
+		j := sources[i]
+		if j < 0 {
+			result.Patches = append(result.Patches, Patch{
+				Type:    PatchCreate,
+				Path:    childPath(path, i),
+				NewNode: child,
+			})
+			continue
+		}
+		if !stay[i] {
+			result.Patches = append(result.Patches, Patch{
+				Type:    PatchReorder,
+				Path:    childPath(path, i),
+				From:    j,
+				OldNode: oldChildren[j],
+				NewNode: child,
+			})
+		}
+		diffNodes(oldChildren[j], child, childPath(path, i), result)
+	}
+}
+
+// diffChildrenByIndex diffs children position by position
+func diffChildrenByIndex(oldChildren, newChildren []*dom.Node, path []int, result *DiffResult) {
 	oldLen := len(oldChildren)
 	newLen := len(newChildren)
 	maxLen := oldLen
@@ -95,11 +175,7 @@ func diffChildren(oldChildren, newChildren []*dom.Node, path []int, result *Diff
 		maxLen = newLen
 	}
 
-	// Simple diffing without key optimization for now
-	// TODO: Implement key-based reconciliation for better performance
 	for i := 0; i < maxLen; i++ {
-		childPath := append(path, i)
-
 		var oldChild, newChild *dom.Node
 		if i < oldLen {
 			oldChild = oldChildren[i]
@@ -108,8 +184,68 @@ func diffChildren(oldChildren, newChildren []*dom.Node, path []int, result *Diff
 			newChild = newChildren[i]
 		}
 
-		diffNodes(oldChild, newChild, childPath, result)
+		diffNodes(oldChild, newChild, childPath(path, i), result)
+	}
+}
+
+func hasKeys(children []*dom.Node) bool {
+	for _, child := range children {
+		if child != nil && child.Key != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// childKey identifies a child among its siblings: by key, or by index
+// for children without one
+func childKey(index int, child *dom.Node) string {
+	if child.Key != "" {
+		return "key:" + child.Key
+	}
+	return "index:" + strconv.Itoa(index)
+}
+
+// longestIncreasing marks the positions of a longest strictly increasing
+// subsequence of seq, ignoring negative entries
+func longestIncreasing(seq []int) []bool {
+	// tails[k] is the position of the smallest tail of an increasing
+	// subsequence of length k+1, prev links each position to its predecessor
+	tails := make([]int, 0, len(seq))
+	prev := make([]int, len(seq))
+	for i, v := range seq {
+		if v < 0 {
+			continue
+		}
+		k := sort.Search(len(tails), func(k int) bool { return seq[tails[k]] >= v })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	in := make([]bool, len(seq))
+	if len(tails) == 0 {
+		return in
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		in[i] = true
 	}
+	return in
+}
+
+// childPath returns a copy of path extended with index
+func childPath(path []int, index int) []int {
+	result := make([]int, len(path)+1)
+	copy(result, path)
+	result[len(path)] = index
+	return result
 }
 
 // diffProps compares two property maps
@@ -0,0 +1,102 @@
+package react
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+func keyedList(keys ...string) *dom.Node {
+	children := make([]*dom.Node, len(keys))
+	for i, key := range keys {
+		children[i] = dom.CreateNode(dom.ElementTypeLi, dom.NewStructProps(itemProps{Key: key, Text: key}))
+	}
+	return dom.Ul(dom.DivProps{}, children...)
+}
+
+type itemProps struct {
+	Key  string
+	Text string
+}
+
+// patchSummary describes patches as "type path[from]" for comparison
+func patchSummary(patches []Patch) []string {
+	names := map[PatchType]string{
+		PatchCreate:  "create",
+		PatchUpdate:  "update",
+		PatchDelete:  "delete",
+		PatchReplace: "replace",
+		PatchReorder: "reorder",
+	}
+	summary := make([]string, 0, len(patches))
+	for _, p := range patches {
+		s := names[p.Type]
+		for _, i := range p.Path {
+			s += " " + string(rune('0'+i))
+		}
+		if p.Type == PatchReorder {
+			s += " from " + string(rune('0'+p.From))
+		}
+		summary = append(summary, s)
+	}
+	return summary
+}
+
+func TestDiffKeyedChildren(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      []string
+		new      []string
+		expected []string
+	}{
+		{"insert at top", []string{"a", "b", "c"}, []string{"x", "a", "b", "c"}, []string{"create 0"}},
+		{"remove from middle", []string{"a", "b", "c"}, []string{"a", "c"}, []string{"delete 1"}},
+		{"move last to front", []string{"a", "b", "c", "d"}, []string{"d", "a", "b", "c"}, []string{"reorder 0 from 3"}},
+		{"swap", []string{"a", "b", "c"}, []string{"c", "b", "a"}, []string{"reorder 0 from 2", "reorder 1 from 1"}},
+		{"remove around", []string{"a", "b", "c"}, []string{"b"}, []string{"delete 2", "delete 0"}},
+		{"replace all", []string{"a", "b"}, []string{"c"}, []string{"delete 1", "delete 0", "create 0"}},
+		{"unchanged", []string{"a", "b"}, []string{"a", "b"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := patchSummary(Diff(keyedList(tt.old...), keyedList(tt.new...)).Patches)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDiffKeyedChildrenUpdatesMovedNode(t *testing.T) {
+	old := dom.Ul(dom.DivProps{},
+		dom.CreateNode(dom.ElementTypeLi, dom.NewStructProps(itemProps{Key: "a", Text: "A"})),
+		dom.CreateNode(dom.ElementTypeLi, dom.NewStructProps(itemProps{Key: "b", Text: "B"})),
+	)
+	updated := dom.Ul(dom.DivProps{},
+		dom.CreateNode(dom.ElementTypeLi, dom.NewStructProps(itemProps{Key: "b", Text: "B2"})),
+		dom.CreateNode(dom.ElementTypeLi, dom.NewStructProps(itemProps{Key: "a", Text: "A"})),
+	)
+
+	patches := Diff(old, updated).Patches
+	got := patchSummary(patches)
+	expected := []string{"reorder 0 from 1", "update 0"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if patches[1].Props["text"] != "B2" {
+		t.Errorf("expected text change to B2, got %v", patches[1].Props)
+	}
+}
+
+func TestDiffUnkeyedChildrenByIndex(t *testing.T) {
+	old := dom.Div(dom.DivProps{}, dom.Text("a"), dom.Text("b"))
+	updated := dom.Div(dom.DivProps{}, dom.Text("x"), dom.Text("a"), dom.Text("b"))
+
+	got := patchSummary(Diff(old, updated).Patches)
+	expected := []string{"update 0", "update 1", "create 2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
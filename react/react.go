@@ -1,6 +1,7 @@
 package react
 
 import (
+	"fmt"
 	"reflect"
 	"sync"
 
@@ -42,6 +43,7 @@ type Hook interface {
 type Patch struct {
 	Type    PatchType
 	Path    []int                  // Path to the node in the tree
+	From    int                    // Index the node moved from among its old siblings (PatchReorder)
 	OldNode *dom.Node              // Old node (for updates/deletes)
 	NewNode *dom.Node              // New node (for creates/updates)
 	Props   map[string]interface{} // Property changes
@@ -50,22 +52,59 @@ type Patch struct {
 // React represents the main React instance
 type React struct {
 	renderer    Renderer
-	hookIndex   int
-	hooks       []Hook
 	isRendering bool
-	updateQueue []func()
+	current     *Instance            // instance whose hooks are being called
+	instances   map[string]*Instance // mounted instances by path
+	updateQueue []func()             // effects to run once rendering completes
 	mu          sync.RWMutex
+
+	onUpdate      func()
+	updatePending bool
+}
+
+// Instance is a mounted component with its own hook list.
+// Instances are identified by their path in the tree, so a component keeps
+// its state as long as it is rendered at the same position.
+type Instance struct {
+	Path string
+
+	hooks     []Hook
+	hookIndex int
+	dirty     bool
+	seen      bool          // rendered during the current render pass
+	unmounted bool          // removed from the tree, its state can no longer be set
+	children  []*dom.Node   // children passed to the component node
+	scope     *contextScope // providers above the instance, nearest first
+}
+
+// Dirty reports whether the instance's state changed since it was last rendered
+func (inst *Instance) Dirty() bool {
+	return inst.dirty
+}
+
+// Hooks returns the hooks of the instance in call order
+func (inst *Instance) Hooks() []Hook {
+	return inst.hooks
 }
 
 // NewReact creates a new React instance
 func NewReact(renderer Renderer) *React {
 	return &React{
 		renderer:    renderer,
-		hooks:       make([]Hook, 0),
+		instances:   make(map[string]*Instance),
 		updateQueue: make([]func(), 0),
 	}
 }
 
+// OnUpdate registers fn to be called, from a separate goroutine, when state
+// changes and the tree must be rendered again. Multiple state changes
+// before the next Render are coalesced into one call.
+func (r *React) OnUpdate(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onUpdate = fn
+}
+
 // ========================================
 // Fiber Architecture (simplified React Fiber)
 // ========================================
@@ -120,6 +159,8 @@ const (
 type StateHookValue struct {
 	State    interface{}
 	SetState func(interface{})
+
+	version int // incremented each time the state is set
 }
 
 // GetType returns the hook type
@@ -176,10 +217,11 @@ func (r *React) UseState(initialState interface{}) (interface{}, func(interface{
 	}
 
 	// Get or create hook
+	inst := r.current
 	var hook *StateHookValue
-	if r.hookIndex < len(r.hooks) {
+	if inst.hookIndex < len(inst.hooks) {
 		// Use existing hook
-		if existingHook, ok := r.hooks[r.hookIndex].(*StateHookValue); ok {
+		if existingHook, ok := inst.hooks[inst.hookIndex].(*StateHookValue); ok {
 			hook = existingHook
 		} else {
 			panic("Hook type mismatch")
@@ -193,23 +235,38 @@ func (r *React) UseState(initialState interface{}) (interface{}, func(interface{
 		// Create setState function
 		hook.SetState = func(newState interface{}) {
 			r.mu.Lock()
+			// Handle functional updates outside the lock, so that the
+			// updater can call back into React. It runs again if the
+			// state was set meanwhile.
+			if updateFunc, ok := newState.(func(interface{}) interface{}); ok {
+				for {
+					prev, version := hook.State, hook.version
+					r.mu.Unlock()
+					newState = updateFunc(prev)
+					r.mu.Lock()
+					if hook.version == version {
+						break
+					}
+				}
+			}
 			defer r.mu.Unlock()
 
-			// Handle functional updates
-			if updateFunc, ok := newState.(func(interface{}) interface{}); ok {
-				hook.State = updateFunc(hook.State)
-			} else {
-				hook.State = newState
+			// the instance is gone, like when a timer fires after unmount
+			if inst.unmounted {
+				return
 			}
+			hook.State = newState
+			hook.version++
 
-			// Schedule re-render
+			// Schedule re-render of the owning instance
+			inst.dirty = true
 			r.scheduleUpdate()
 		}
 
-		r.hooks = append(r.hooks, hook)
+		inst.hooks = append(inst.hooks, hook)
 	}
 
-	r.hookIndex++
+	inst.hookIndex++
 	return hook.State, hook.SetState
 }
 
@@ -223,10 +280,11 @@ func (r *React) UseEffect(effect func(), cleanup func(), deps []interface{}) {
 	}
 
 	// Get or create hook
+	inst := r.current
 	var hook *EffectHookValue
-	if r.hookIndex < len(r.hooks) {
+	if inst.hookIndex < len(inst.hooks) {
 		// Use existing hook
-		if existingHook, ok := r.hooks[r.hookIndex].(*EffectHookValue); ok {
+		if existingHook, ok := inst.hooks[inst.hookIndex].(*EffectHookValue); ok {
 			hook = existingHook
 		} else {
 			panic("Hook type mismatch")
@@ -239,20 +297,21 @@ func (r *React) UseEffect(effect func(), cleanup func(), deps []interface{}) {
 			Deps:    deps,
 			HasRun:  false,
 		}
-		r.hooks = append(r.hooks, hook)
+		inst.hooks = append(inst.hooks, hook)
 	}
 
 	// Check if effect should run
 	shouldRun := !hook.HasRun || r.depsChanged(hook.Deps, deps)
 
 	if shouldRun {
-		// Run cleanup if it exists
-		if hook.Cleanup != nil && hook.HasRun {
-			hook.Cleanup()
-		}
-
-		// Schedule effect to run after render
+		// Schedule the previous cleanup and the effect to run after render,
+		// outside the lock so they can set state
+		prevCleanup := hook.Cleanup
+		hasRun := hook.HasRun
 		r.updateQueue = append(r.updateQueue, func() {
+			if prevCleanup != nil && hasRun {
+				prevCleanup()
+			}
 			effect()
 			hook.HasRun = true
 		})
@@ -264,7 +323,19 @@ func (r *React) UseEffect(effect func(), cleanup func(), deps []interface{}) {
 		hook.Cleanup = cleanup
 	}
 
-	r.hookIndex++
+	inst.hookIndex++
+}
+
+// UseChildren returns the children passed to the component currently
+// rendering through dom.CreateComponent
+func (r *React) UseChildren() []*dom.Node {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.isRendering {
+		panic("useChildren can only be called during rendering")
+	}
+	return r.current.children
 }
 
 // depsChanged checks if dependencies have changed
@@ -282,74 +353,260 @@ func (r *React) depsChanged(oldDeps, newDeps []interface{}) bool {
 	return false
 }
 
-// scheduleUpdate schedules a re-render
+// scheduleUpdate schedules a re-render, the caller must hold r.mu
 func (r *React) scheduleUpdate() {
-	// In a real implementation, this would use a scheduler
-	// For now, we'll just mark that an update is needed
+	if r.updatePending {
+		return
+	}
+	r.updatePending = true
+	onUpdate := r.onUpdate
+	// notify asynchronously: state is usually set from within an event
+	// handler, where the program cannot receive messages yet
 	go func() {
-		// This would trigger a re-render in the next tick
-		r.forceUpdate()
+		r.forceUpdate(onUpdate)
 	}()
 }
 
-// forceUpdate forces a re-render
-func (r *React) forceUpdate() {
-	// This would be called by the scheduler
-	// Implementation depends on the specific use case
+// forceUpdate asks the host to render again
+func (r *React) forceUpdate(onUpdate func()) {
+	if onUpdate != nil {
+		onUpdate()
+	}
+}
+
+// ========================================
+// React Render Pass
+// ========================================
+
+// Render runs a render pass: root is rendered as the root component instance
+// so it can call hooks, then every component node of its tree is rendered,
+// instances that were not rendered during the pass are unmounted, and queued
+// effects run. It returns the tree with its component nodes expanded.
+func (r *React) Render(root func() *dom.Node) *dom.Node {
+	r.mu.Lock()
+	r.updatePending = false
+	for _, inst := range r.instances {
+		inst.seen = false
+	}
+	r.mu.Unlock()
+
+	node := r.expand(r.RenderComponent("", root), "", nil)
+
+	r.unmountUnseen()
+	r.flushEffects()
+	return node
+}
+
+// RenderComponent renders fn as the component instance at path.
+// Hooks called by fn on r resolve to that instance.
+func (r *React) RenderComponent(path string, fn func() *dom.Node) *dom.Node {
+	r.mu.Lock()
+	inst := r.instances[path]
+	if inst == nil {
+		inst = &Instance{Path: path}
+		r.instances[path] = inst
+	}
+	inst.seen = true
+	inst.dirty = false
+	inst.hookIndex = 0
+
+	prevInstance, prevRendering := r.current, r.isRendering
+	r.current = inst
+	r.isRendering = true
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.current = prevInstance
+		r.isRendering = prevRendering
+		r.mu.Unlock()
+	}()
+
+	return fn()
+}
+
+// expand returns node with its component nodes rendered, depth first.
+// Each component node is replaced with a copy whose only child is its
+// output, and whose State is the instance backing it, keyed by its path and
+// component function so that a different component at the same position
+// starts fresh. Nodes holding components are copied as well: node itself is
+// left untouched, so that a node kept across renders renders again.
+// scope holds the context providers enclosing node.
+func (r *React) expand(node *dom.Node, path string, scope *contextScope) *dom.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Type == dom.ElementTypeComponent && node.Component != nil {
+		if provider, ok := node.Props.(dom.StructProps[ContextProvider]); ok {
+			scope = &contextScope{
+				context: provider.Value.Context,
+				value:   provider.Value.Value,
+				parent:  scope,
+			}
+		}
+		instPath := path + "@" + componentID(node.Component)
+		children := node.Children
+		component, props := node.Component, node.Props
+		out := r.RenderComponent(instPath, func() *dom.Node {
+			r.current.children = children
+			r.current.scope = scope
+			return component(props)
+		})
+
+		inst := r.Instance(instPath)
+		expanded := *node
+		expanded.State = inst
+		expanded.Hooks = make([]dom.Hook, len(inst.hooks))
+		expanded.EffectCleanups = nil
+		for i, hook := range inst.hooks {
+			expanded.Hooks[i] = hook
+			if effect, ok := hook.(*EffectHookValue); ok && effect.Cleanup != nil {
+				expanded.EffectCleanups = append(expanded.EffectCleanups, effect.Cleanup)
+			}
+		}
+		expanded.Children = nil
+		if out != nil {
+			expanded.Children = []*dom.Node{out}
+		}
+		node = &expanded
+	}
+
+	var children []*dom.Node
+	for i, child := range node.Children {
+		if child == nil {
+			continue
+		}
+		out := r.expand(child, dom.ChildPath(path, i, child), scope)
+		if out == child {
+			continue
+		}
+		if children == nil {
+			children = make([]*dom.Node, len(node.Children))
+			copy(children, node.Children)
+		}
+		children[i] = out
+	}
+	if children != nil {
+		copied := *node
+		copied.Children = children
+		node = &copied
+	}
+	return node
+}
+
+// componentID identifies a component function
+func componentID(component dom.Component) string {
+	return fmt.Sprintf("%x", reflect.ValueOf(component).Pointer())
+}
+
+// Instance returns the mounted instance at path, or nil
+func (r *React) Instance(path string) *Instance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.instances[path]
+}
+
+// unmountUnseen removes instances not rendered in the last pass,
+// running the cleanups of their effects
+func (r *React) unmountUnseen() {
+	r.mu.Lock()
+	var unmounted []*Instance
+	for path, inst := range r.instances {
+		if !inst.seen {
+			inst.unmounted = true
+			unmounted = append(unmounted, inst)
+			delete(r.instances, path)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, inst := range unmounted {
+		for _, hook := range inst.hooks {
+			if effect, ok := hook.(*EffectHookValue); ok && effect.HasRun && effect.Cleanup != nil {
+				effect.Cleanup()
+			}
+		}
+	}
+}
+
+// flushEffects runs the effects queued during rendering
+func (r *React) flushEffects() {
+	r.mu.Lock()
+	queue := r.updateQueue
+	r.updateQueue = make([]func(), 0)
+	r.mu.Unlock()
+
+	for _, effect := range queue {
+		effect()
+	}
 }
 
 // ========================================
 // React Context API
 // ========================================
 
-// Context represents a React context
+// Context carries a value down the tree without passing it through props.
+// A value is scoped to a subtree with Provider and read with UseContext.
 type Context struct {
 	DefaultValue interface{}
-	Providers    []*ContextProvider
 }
 
-// ContextProvider provides context value to children
+// ContextProvider is the props of a provider node
 type ContextProvider struct {
-	Context  *Context
-	Value    interface{}
-	Children []*dom.Node
+	Context *Context
+	Value   interface{}
 }
 
-func (c *ContextProvider) Render() *dom.Node {
-	// TODO
-	return nil
+// contextScope is one provider in the chain enclosing an instance
+type contextScope struct {
+	context *Context
+	value   interface{}
+	parent  *contextScope
+}
+
+// lookup returns the value of the nearest provider of context
+func (s *contextScope) lookup(context *Context) (interface{}, bool) {
+	for ; s != nil; s = s.parent {
+		if s.context == context {
+			return s.value, true
+		}
+	}
+	return nil, false
 }
 
 // CreateContext creates a new React context
 func CreateContext(defaultValue interface{}) *Context {
 	return &Context{
 		DefaultValue: defaultValue,
-		Providers:    make([]*ContextProvider, 0),
 	}
 }
 
-// Provider creates a context provider element
+// Provider creates a node that provides value to the components rendered
+// in children. Providers can be nested, the nearest one wins.
+// Since the whole tree is rendered on every pass, consumers read the new
+// value as soon as the provider is rendered with it.
 func (c *Context) Provider(value interface{}, children ...*dom.Node) *dom.Node {
-	provider := &ContextProvider{
-		Context:  c,
-		Value:    value,
-		Children: make([]*dom.Node, len(children)),
-	}
-
-	copy(provider.Children, children)
-
+	// the value itself is picked up by expand
 	return dom.CreateComponent(func(props dom.Props) *dom.Node {
-		panic("TODO Provider")
-		return nil
-	}, dom.NewStructProps(dom.ElementProps{}), children...)
+		return dom.Fragment(children...)
+	}, dom.NewStructProps(ContextProvider{
+		Context: c,
+		Value:   value,
+	}))
 }
 
-// UseContext implements React's useContext hook
+// UseContext implements React's useContext hook: it returns the value of the
+// nearest Provider of context above the component currently rendering, or
+// the context's default value if there is none
 func (r *React) UseContext(context *Context) interface{} {
-	// In a real implementation, this would walk up the fiber tree
-	// to find the nearest provider
-	if len(context.Providers) > 0 {
-		return context.Providers[len(context.Providers)-1].Value
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.isRendering {
+		panic("useContext can only be called during rendering")
+	}
+	if value, ok := r.current.scope.lookup(context); ok {
+		return value
 	}
 	return context.DefaultValue
 }
@@ -0,0 +1,341 @@
+package react
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xhd2015/go-dom-tui/dom"
+)
+
+func TestUseStatePersistsAcrossRenders(t *testing.T) {
+	r := NewReact(nil)
+
+	var setCount func(interface{})
+	render := func() *dom.Node {
+		count, set := r.UseState(0)
+		setCount = set
+		return dom.Text(string(rune('0' + count.(int))))
+	}
+
+	if got := r.Render(render).Text; got != "0" {
+		t.Fatalf("expected initial state 0, got %q", got)
+	}
+	setCount(func(prev interface{}) interface{} { return prev.(int) + 1 })
+	if !r.Instance("").Dirty() {
+		t.Errorf("expected instance to be dirty after setState")
+	}
+	if got := r.Render(render).Text; got != "1" {
+		t.Fatalf("expected state 1 after update, got %q", got)
+	}
+	if r.Instance("").Dirty() {
+		t.Errorf("expected instance to be clean after render")
+	}
+}
+
+func TestSetStateNotifiesOnce(t *testing.T) {
+	r := NewReact(nil)
+	updates := make(chan struct{}, 10)
+	r.OnUpdate(func() { updates <- struct{}{} })
+
+	var setValue func(interface{})
+	r.Render(func() *dom.Node {
+		_, set := r.UseState("a")
+		setValue = set
+		return nil
+	})
+
+	setValue("b")
+	setValue("c")
+
+	select {
+	case <-updates:
+	case <-time.After(time.Second):
+		t.Fatalf("expected an update notification")
+	}
+	select {
+	case <-updates:
+		t.Fatalf("expected updates before the next render to be coalesced")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSetStateUpdaterCallsReact(t *testing.T) {
+	r := NewReact(nil)
+
+	var setA, setB func(interface{})
+	r.Render(func() *dom.Node {
+		_, setA = r.UseState(0)
+		_, setB = r.UseState(0)
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		setA(func(prev interface{}) interface{} {
+			setB(1)
+			r.Instance("")
+			return prev.(int) + 1
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected an updater calling into React not to deadlock")
+	}
+	if hooks := r.Instance("").Hooks(); hooks[0].GetValue() != 1 || hooks[1].GetValue() != 1 {
+		t.Errorf("expected both states set, got %v and %v", hooks[0].GetValue(), hooks[1].GetValue())
+	}
+}
+
+func TestSetStateAfterUnmount(t *testing.T) {
+	r := NewReact(nil)
+	updates := make(chan struct{}, 10)
+	r.OnUpdate(func() { updates <- struct{}{} })
+
+	var setValue func(interface{})
+	widget := func(props dom.Props) *dom.Node {
+		_, setValue = r.UseState(0)
+		return nil
+	}
+	show := true
+	render := func() *dom.Node {
+		if show {
+			return dom.CreateComponent(widget, nil)
+		}
+		return nil
+	}
+	r.Render(render)
+	show = false
+	r.Render(render)
+
+	setValue(1)
+	select {
+	case <-updates:
+		t.Fatalf("expected no render for state set after unmount")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInstancesKeepSeparateHooks(t *testing.T) {
+	r := NewReact(nil)
+	setters := map[string]func(interface{}){}
+	values := map[string]interface{}{}
+
+	child := func(path string) *dom.Node {
+		return r.RenderComponent(path, func() *dom.Node {
+			v, set := r.UseState(path)
+			setters[path] = set
+			values[path] = v
+			return nil
+		})
+	}
+	render := func() *dom.Node {
+		child("/a")
+		child("/b")
+		return nil
+	}
+
+	r.Render(render)
+	setters["/a"]("changed")
+	r.Render(render)
+
+	if values["/a"] != "changed" || values["/b"] != "/b" {
+		t.Errorf("unexpected values %v", values)
+	}
+}
+
+func TestEffectCleanupOnUnmount(t *testing.T) {
+	r := NewReact(nil)
+	var events []string
+	showChild := true
+
+	render := func() *dom.Node {
+		if showChild {
+			r.RenderComponent("/child", func() *dom.Node {
+				r.UseEffect(func() { events = append(events, "mount") }, func() { events = append(events, "cleanup") }, []interface{}{})
+				return nil
+			})
+		}
+		return nil
+	}
+
+	r.Render(render)
+	r.Render(render)
+	showChild = false
+	r.Render(render)
+
+	if len(events) != 2 || events[0] != "mount" || events[1] != "cleanup" {
+		t.Errorf("expected mount then cleanup, got %v", events)
+	}
+	if r.Instance("/child") != nil {
+		t.Errorf("expected child instance to be unmounted")
+	}
+}
+
+type counterProps struct {
+	Key   string
+	Label string
+}
+
+func TestComponentNodesKeepStateByKey(t *testing.T) {
+	r := NewReact(nil)
+	setters := map[string]func(interface{}){}
+
+	counter := func(props dom.Props) *dom.Node {
+		p := dom.ExtractProps[counterProps](props)
+		count, set := r.UseState(0)
+		setters[p.Key] = set
+		return dom.Text(p.Label + ":" + string(rune('0'+count.(int))))
+	}
+	order := []string{"a", "b"}
+	render := func() *dom.Node {
+		items := make([]*dom.Node, 0, len(order))
+		for _, key := range order {
+			items = append(items, dom.CreateComponent(counter, dom.NewStructProps(counterProps{Key: key, Label: key})))
+		}
+		return dom.Div(dom.DivProps{}, items...)
+	}
+
+	r.Render(render)
+	setters["b"](5)
+	order = []string{"b", "a"}
+	root := r.Render(render)
+
+	first := root.Children[0]
+	if first.Type != dom.ElementTypeComponent || first.State == nil {
+		t.Fatalf("expected an expanded component node, got %+v", first)
+	}
+	if got := first.Children[0].Text; got != "b:5" {
+		t.Errorf("expected keyed component to keep its state, got %q", got)
+	}
+	if got := root.Children[1].Children[0].Text; got != "a:0" {
+		t.Errorf("expected a:0, got %q", got)
+	}
+}
+
+func TestComponentNodeKeptAcrossRenders(t *testing.T) {
+	r := NewReact(nil)
+	var setLabel func(interface{})
+	label := func(props dom.Props) *dom.Node {
+		value, set := r.UseState("a")
+		setLabel = set
+		return dom.Text(value.(string))
+	}
+	node := dom.CreateComponent(label, nil)
+	render := func() *dom.Node {
+		return dom.Div(dom.DivProps{}, node)
+	}
+
+	r.Render(render)
+	setLabel("b")
+	root := r.Render(render)
+
+	if got := root.Children[0].Children[0].Text; got != "b" {
+		t.Errorf("expected the kept node to render again, got %q", got)
+	}
+	if node.State != nil || len(node.Children) != 0 {
+		t.Errorf("expected the node passed to Render to be left untouched, got %+v", node)
+	}
+}
+
+func TestComponentUnmountRunsCleanups(t *testing.T) {
+	r := NewReact(nil)
+	cleanups := 0
+	widget := func(props dom.Props) *dom.Node {
+		r.UseEffect(func() {}, func() { cleanups++ }, []interface{}{})
+		return dom.Text("widget")
+	}
+	show := true
+	render := func() *dom.Node {
+		if show {
+			return dom.Div(dom.DivProps{}, dom.CreateComponent(widget, nil))
+		}
+		return dom.Div(dom.DivProps{})
+	}
+
+	root := r.Render(render)
+	if len(root.Children[0].EffectCleanups) != 1 {
+		t.Errorf("expected the node to expose its effect cleanup")
+	}
+	r.Render(render)
+	show = false
+	r.Render(render)
+
+	if cleanups != 1 {
+		t.Errorf("expected 1 cleanup on unmount, got %d", cleanups)
+	}
+}
+
+func TestUseChildren(t *testing.T) {
+	r := NewReact(nil)
+	box := func(props dom.Props) *dom.Node {
+		return dom.Div(dom.DivProps{}, r.UseChildren()...)
+	}
+	root := r.Render(func() *dom.Node {
+		return dom.CreateComponent(box, nil, dom.Text("x"), dom.Text("y"))
+	})
+
+	div := root.Children[0]
+	if len(div.Children) != 2 || div.Children[1].Text != "y" {
+		t.Errorf("expected children to be passed through, got %+v", div.Children)
+	}
+}
+
+func TestUseContextNearestProvider(t *testing.T) {
+	r := NewReact(nil)
+	theme := CreateContext("default")
+
+	var got []string
+	consumer := func(props dom.Props) *dom.Node {
+		value := r.UseContext(theme).(string)
+		got = append(got, value)
+		return dom.Text(value)
+	}
+
+	r.Render(func() *dom.Node {
+		return dom.Div(dom.DivProps{},
+			dom.CreateComponent(consumer, nil),
+			theme.Provider("dark",
+				dom.CreateComponent(consumer, nil),
+				theme.Provider("light",
+					dom.Div(dom.DivProps{}, dom.CreateComponent(consumer, nil)),
+				),
+			),
+			dom.CreateComponent(consumer, nil),
+		)
+	})
+
+	expected := []string{"default", "dark", "light", "default"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestUseContextValueChange(t *testing.T) {
+	r := NewReact(nil)
+	locale := CreateContext("en")
+
+	var setLocale func(interface{})
+	var got string
+	consumer := func(props dom.Props) *dom.Node {
+		got = r.UseContext(locale).(string)
+		return dom.Text(got)
+	}
+	render := func() *dom.Node {
+		value, set := r.UseState("en")
+		setLocale = set
+		return locale.Provider(value, dom.CreateComponent(consumer, nil))
+	}
+
+	r.Render(render)
+	if got != "en" {
+		t.Fatalf("expected en, got %q", got)
+	}
+	setLocale("fr")
+	r.Render(render)
+	if got != "fr" {
+		t.Errorf("expected consumer to read fr after the provider value changed, got %q", got)
+	}
+}
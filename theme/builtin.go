@@ -0,0 +1,103 @@
+package theme
+
+import (
+	"github.com/xhd2015/go-dom-tui/colors"
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+// elements are the element styles of the built-in themes, in terms of
+// their palettes
+var elements = map[string]styles.Style{
+	"h1":             {Color: "onPrimary", BackgroundColor: "primary", Bold: true},
+	"h2":             {Color: "onSecondary", BackgroundColor: "secondary", Bold: true},
+	"text":           {Color: "muted"},
+	"button":         {Color: "onAccent", BackgroundColor: "accent", Bold: true},
+	"li":             {Color: "muted"},
+	ListItemSelected: {Color: "success", Bold: true},
+	InputPrompt:      {Color: "highlight", Bold: true},
+	InputPlaceholder: {Color: "muted", Italic: true},
+	Container:        {BorderColor: "border"},
+	Success:          {Color: "success", Bold: true},
+	Error:            {Color: "error", Bold: true},
+}
+
+// Dark returns the theme for terminals with a dark background, the default
+func Dark() Theme {
+	return Theme{
+		Name: "dark",
+		Palette: map[string]string{
+			"primary":     "#7D56F4",
+			"onPrimary":   "#FAFAFA",
+			"secondary":   "#F25D94",
+			"onSecondary": "#FFF7DB",
+			"accent":      "#04B575",
+			"onAccent":    "#FFF",
+			"muted":       "#626262",
+			"highlight":   "#FFFF00",
+			"border":      colors.PURPLE_PRIMARY,
+			"success":     colors.GREEN_SUCCESS,
+			"error":       colors.RED_ERROR,
+		},
+		Elements: elements,
+	}.Clone()
+}
+
+// Light returns the theme for terminals with a light background
+func Light() Theme {
+	return Theme{
+		Name: "light",
+		Palette: map[string]string{
+			"primary":     "#5A3FC0",
+			"onPrimary":   "#FFFFFF",
+			"secondary":   "#C2185B",
+			"onSecondary": "#FFFFFF",
+			"accent":      "#00796B",
+			"onAccent":    "#FFFFFF",
+			"muted":       "#4A4A4A",
+			"highlight":   "#B8860B",
+			"border":      "#5A3FC0",
+			"success":     "#1B7F1B",
+			"error":       "#C62828",
+		},
+		Elements: elements,
+	}.Clone()
+}
+
+// HighContrast returns a theme of pure colors on black, without grey
+// text, for users who need strong contrast
+func HighContrast() Theme {
+	t := Theme{
+		Name: "high-contrast",
+		Palette: map[string]string{
+			"primary":     "#FFFF00",
+			"onPrimary":   "#000000",
+			"secondary":   "#00FFFF",
+			"onSecondary": "#000000",
+			"accent":      "#FFFFFF",
+			"onAccent":    "#000000",
+			"muted":       "#FFFFFF",
+			"highlight":   "#FFFF00",
+			"border":      "#FFFFFF",
+			"success":     "#00FF00",
+			"error":       "#FF5555",
+		},
+		Elements: elements,
+	}.Clone()
+	// color alone does not tell the selected item apart
+	t.Elements[ListItemSelected] = styles.Style{Color: "highlight", Bold: true, Underline: true}
+	return t
+}
+
+// Builtin returns the built-in theme with the given name:
+// "dark", "light" or "high-contrast"
+func Builtin(name string) (Theme, bool) {
+	switch name {
+	case "dark":
+		return Dark(), true
+	case "light":
+		return Light(), true
+	case "high-contrast":
+		return HighContrast(), true
+	}
+	return Theme{}, false
+}
@@ -0,0 +1,115 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+// file is the JSON and TOML form of a theme
+type file struct {
+	Name     string                  `json:"name" toml:"name"`
+	Extends  string                  `json:"extends" toml:"extends"`
+	Palette  map[string]string       `json:"palette" toml:"palette"`
+	Elements map[string]styles.Style `json:"elements" toml:"elements"`
+}
+
+// Load reads a theme from a file, TOML if its name ends with ".toml",
+// see ParseTOML, JSON otherwise, see Parse
+func Load(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	parse := Parse
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		parse = ParseTOML
+	}
+	t, err := parse(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Parse reads a theme from JSON like
+//
+//	{
+//	  "name": "brand",
+//	  "extends": "dark",
+//	  "palette": {"primary": "#FF6600"},
+//	  "elements": {"button": {"color": "#000000", "backgroundColor": "primary", "bold": true}}
+//	}
+//
+// A theme extending a built-in one, see Builtin, starts from its palette
+// and styles: palette colors are replaced one by one, element styles as a
+// whole. The fields of element styles are those of styles.Style.
+func Parse(data []byte) (Theme, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var f file
+	if err := dec.Decode(&f); err != nil {
+		return Theme{}, fmt.Errorf("invalid theme: %w", err)
+	}
+	return f.theme()
+}
+
+// ParseTOML reads a theme from TOML like
+//
+//	name = "brand"
+//	extends = "dark"
+//
+//	[palette]
+//	primary = "#FF6600"
+//
+//	[elements.button]
+//	color = "#000000"
+//	backgroundColor = "primary"
+//	bold = true
+//
+//	[elements."input.prompt"]
+//	color = "accent"
+//
+// with the same fields as the JSON of Parse. The keys of parts, which
+// have a dot, are quoted.
+func ParseTOML(data []byte) (Theme, error) {
+	var f file
+	meta, err := toml.Decode(string(data), &f)
+	if err != nil {
+		return Theme{}, fmt.Errorf("invalid theme: %w", err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return Theme{}, fmt.Errorf("invalid theme: unknown field %q", undecoded[0].String())
+	}
+	return f.theme()
+}
+
+// theme builds the theme f describes
+func (f file) theme() (Theme, error) {
+	t := Theme{Palette: map[string]string{}, Elements: map[string]styles.Style{}}
+	if f.Extends != "" {
+		base, ok := Builtin(f.Extends)
+		if !ok {
+			return Theme{}, fmt.Errorf("invalid theme: unknown theme %q to extend", f.Extends)
+		}
+		t = base
+	}
+	t.Name = f.Name
+	for name, color := range f.Palette {
+		t.Palette[name] = color
+	}
+	for key, style := range f.Elements {
+		if !slices.Contains(ElementKeys, key) {
+			return Theme{}, fmt.Errorf("invalid theme: unknown element %q", key)
+		}
+		t.Elements[key] = style
+	}
+	return t, nil
+}
@@ -0,0 +1,76 @@
+// Package theme defines the colors and styles elements are rendered with
+package theme
+
+import (
+	"github.com/xhd2015/go-dom-tui/dom"
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+// Theme is a named palette and the styles of the elements
+type Theme struct {
+	Name string `json:"name"`
+
+	// Palette names colors. Styles, those of the theme as well as the Style
+	// props of nodes, can use a name in place of a color, like "primary".
+	Palette map[string]string `json:"palette"`
+
+	// Elements are the styles of the elements by type, like "h1" or
+	// "button", and of their parts, like "input.prompt", see ElementKeys.
+	// They apply before the Style props of nodes.
+	Elements map[string]styles.Style `json:"elements"`
+}
+
+// Parts of elements styled apart from them
+const (
+	InputPrompt      = "input.prompt"      // the ">" before the value
+	InputText        = "input.text"        // the value
+	InputPlaceholder = "input.placeholder" // shown while the value is empty
+	ListItemSelected = "li.selected"
+	Container        = "container" // bordered divs of the string renderer
+	Success          = "success"
+	Error            = "error"
+)
+
+// ElementKeys are the keys Elements can style
+var ElementKeys = []string{
+	dom.ElementTypeText, dom.ElementTypeDiv, dom.ElementTypeHDiv, dom.ElementTypeZDiv,
+	dom.ElementTypeSpan, dom.ElementTypeH1, dom.ElementTypeH2, dom.ElementTypeP,
+	dom.ElementTypeInput, dom.ElementTypeTextArea, dom.ElementTypeButton,
+	dom.ElementTypeUl, dom.ElementTypeLi, dom.ElementTypeScrollView,
+	InputPrompt, InputText, InputPlaceholder, ListItemSelected, Container, Success, Error,
+}
+
+// Color returns the color of the palette named name, or name itself if
+// the palette has none, for colors written out like "#FF0000"
+func (t Theme) Color(name string) string {
+	if c, ok := t.Palette[name]; ok {
+		return c
+	}
+	return name
+}
+
+// Resolve replaces the palette names in the colors of style
+func (t Theme) Resolve(style styles.Style) styles.Style {
+	style.Color = t.Color(style.Color)
+	style.BackgroundColor = t.Color(style.BackgroundColor)
+	style.BorderColor = t.Color(style.BorderColor)
+	return style
+}
+
+// Element returns the style of an element or part, with the palette names resolved
+func (t Theme) Element(key string) styles.Style {
+	return t.Resolve(t.Elements[key])
+}
+
+// Clone returns a copy of the theme that can be changed without
+// changing t
+func (t Theme) Clone() Theme {
+	c := Theme{Name: t.Name, Palette: make(map[string]string, len(t.Palette)), Elements: make(map[string]styles.Style, len(t.Elements))}
+	for k, v := range t.Palette {
+		c.Palette[k] = v
+	}
+	for k, v := range t.Elements {
+		c.Elements[k] = v
+	}
+	return c
+}
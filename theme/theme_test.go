@@ -0,0 +1,156 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xhd2015/go-dom-tui/styles"
+)
+
+func TestParse(t *testing.T) {
+	th, err := Parse([]byte(`{
+		"name": "brand",
+		"extends": "dark",
+		"palette": {"primary": "#FF6600", "brand": "#123456"},
+		"elements": {"button": {"color": "brand", "bold": true}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Name != "brand" {
+		t.Errorf("Name = %q, want brand", th.Name)
+	}
+	if got := th.Color("primary"); got != "#FF6600" {
+		t.Errorf("primary = %q, want the replaced #FF6600", got)
+	}
+	if got, want := th.Color("secondary"), Dark().Color("secondary"); got != want {
+		t.Errorf("secondary = %q, want %q from dark", got, want)
+	}
+	if got, want := th.Element("button"), (styles.Style{Color: "#123456", Bold: true}); got != want {
+		t.Errorf("button = %+v, want %+v", got, want)
+	}
+	if got, want := th.Element("h1").BackgroundColor, "#FF6600"; got != want {
+		t.Errorf("h1 background = %q, want the new primary %q", got, want)
+	}
+	// extending does not change the built-in theme
+	if got := Dark().Color("primary"); got == "#FF6600" {
+		t.Errorf("dark primary changed to %q", got)
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	th, err := ParseTOML([]byte(`
+name = "brand"
+extends = "light"
+
+[palette]
+brand = "#123456"
+
+[elements.button]
+color = "brand"
+bold = true
+
+[elements."input.prompt"]
+color = "#FF0000"
+paddingLeft = 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := th.Element("button"), (styles.Style{Color: "#123456", Bold: true}); got != want {
+		t.Errorf("button = %+v, want %+v", got, want)
+	}
+	if got := th.Element(InputPrompt); got.Color != "#FF0000" || got.PaddingLeft == nil || *got.PaddingLeft != 1 {
+		t.Errorf("input.prompt = %+v, want color #FF0000 and padding 1", got)
+	}
+	if got, want := th.Color("primary"), Light().Color("primary"); got != want {
+		t.Errorf("primary = %q, want %q from light", got, want)
+	}
+
+	for _, data := range []string{
+		"[elements.headline]\nbold = true",
+		"[elements.h1]\nbolder = true",
+		"[colors]\nprimary = \"#FFFFFF\"",
+	} {
+		if _, err := ParseTOML([]byte(data)); err == nil {
+			t.Errorf("ParseTOML(%q) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown element", `{"elements": {"headline": {"bold": true}}}`, `unknown element "headline"`},
+		{"unknown theme", `{"extends": "solarized"}`, `unknown theme "solarized"`},
+		{"unknown field", `{"colors": {}}`, `unknown field "colors"`},
+		{"unknown style field", `{"elements": {"h1": {"bolder": true}}}`, `unknown field "bolder"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(`{"extends": "high-contrast", "palette": {"muted": "#AAAAAA"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("Load() error = %v, want one naming %s", err, path)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"extends": "high-contrast", "palette": {"muted": "#AAAAAA"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := th.Element("text").Color; got != "#AAAAAA" {
+		t.Errorf("text color = %q, want #AAAAAA", got)
+	}
+
+	path = filepath.Join(t.TempDir(), "theme.toml")
+	if err := os.WriteFile(path, []byte("extends = \"high-contrast\"\n[palette]\nmuted = \"#BBBBBB\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if th, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := th.Element("text").Color; got != "#BBBBBB" {
+		t.Errorf("text color from TOML = %q, want #BBBBBB", got)
+	}
+}
+
+func TestBuiltinElementKeys(t *testing.T) {
+	for _, name := range []string{"dark", "light", "high-contrast"} {
+		th, ok := Builtin(name)
+		if !ok {
+			t.Fatalf("no built-in theme %q", name)
+		}
+		for key, style := range th.Elements {
+			if !slices.Contains(ElementKeys, key) {
+				t.Errorf("%s: unknown element %q", name, key)
+			}
+			// every color of a built-in style is in the palette
+			for _, c := range []string{style.Color, style.BackgroundColor, style.BorderColor} {
+				if _, ok := th.Palette[c]; c != "" && !ok {
+					t.Errorf("%s: %s uses %q, not in the palette", name, key, c)
+				}
+			}
+		}
+	}
+}